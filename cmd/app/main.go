@@ -2,11 +2,31 @@ package main
 
 import (
 	"avito-internship/internal/app"
-	"avito-internship/internal/database"
+	"avito-internship/internal/config"
+	"flag"
+	"log"
+	"os"
 )
 
 func main() {
-	database.Connect()
-	database.Migrate()
-	app.Run()
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "YAML config file; environment variables override its settings")
+	migrate := flag.String("migrate", "", "run a schema migration command (up, down or status) and exit")
+	flag.Parse()
+
+	if *migrate != "" {
+		dbCfg, err := config.LoadDatabase(*configFile)
+		if err != nil {
+			log.Fatalf("Invalid configuration: %s", err)
+		}
+
+		app.RunMigrations(*dbCfg, *migrate)
+		return
+	}
+
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %s", err)
+	}
+
+	app.Run(cfg)
 }
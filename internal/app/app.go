@@ -1,16 +1,91 @@
 package app
 
 import (
+	"avito-internship/internal/auth"
+	"avito-internship/internal/config"
+	"avito-internship/internal/database"
+	"avito-internship/internal/health"
+	"avito-internship/internal/metrics"
+	"avito-internship/internal/services"
 	"avito-internship/internal/transport"
+	"avito-internship/internal/transport/grpcapi"
+	"context"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
-func Run() {
-	router := transport.SetupRouter()
-	log.Println("Starting server on port 8080")
+func Run(cfg *config.Config) {
+	tokens, err := auth.NewTokenManager(cfg.Auth.KeyMap(), cfg.Auth.ActiveKey, cfg.Auth.TokenTTL, cfg.Auth.RefreshTTL)
+	if err != nil {
+		log.Fatalf("Error configuring tokens: %s", err)
+	}
+
+	policy, err := auth.LoadPolicy(cfg.Auth.PolicyFile)
+	if err != nil {
+		log.Fatalf("Error loading access policy: %s", err)
+	}
+
+	if cfg.Auth.DummyLogin {
+		log.Println("/dummyLogin is enabled, for development only")
+	}
+
+	svc, err := NewServices(cfg.Storage, cfg.Database, policy, cfg.Auth.DummyLogin)
+	if err != nil {
+		log.Fatalf("Error configuring storage: %s", err)
+	}
 
-	err := router.Run(":8080")
+	checks, err := readinessChecks(cfg.Storage)
 	if err != nil {
-		log.Fatalf("Error starting server: %s", err)
+		log.Fatalf("Error configuring readiness checks: %s", err)
 	}
+	readiness := health.NewReadiness(checks...)
+
+	router := transport.SetupRouter(tokens, policy, svc, transport.Options{
+		EnableDummyLogin: cfg.Auth.DummyLogin,
+		PageLimits:       services.PageLimits{Default: cfg.Pagination.DefaultLimit, Max: cfg.Pagination.MaxLimit},
+		Readiness:        readiness,
+		ReadinessTimeout: cfg.ReadinessTimeout,
+	})
+
+	// /metrics is served on its own port, so that it is not reachable
+	// through the public API.
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", metrics.Handler())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf("Starting server on %s, gRPC on %s, metrics on %s", cfg.HTTP.Addr(), cfg.GRPC.Addr(), cfg.Metrics.Addr())
+	err = serve(ctx, shutdownPlan{drain: readiness.Drain, drainDelay: cfg.DrainDelay, timeout: cfg.ShutdownTimeout},
+		httpServer("HTTP", &http.Server{
+			Addr:              cfg.HTTP.Addr(),
+			Handler:           router,
+			ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+			ReadTimeout:       cfg.HTTP.ReadTimeout,
+			WriteTimeout:      cfg.HTTP.WriteTimeout,
+			IdleTimeout:       cfg.HTTP.IdleTimeout,
+		}),
+		grpcServer(grpcapi.NewServer(tokens, policy, svc, services.PageLimits{
+			Default: cfg.Pagination.GRPCDefaultPageSize,
+			Max:     cfg.Pagination.GRPCMaxPageSize,
+		}), cfg.GRPC.Addr()),
+		httpServer("metrics", &http.Server{
+			Addr:              cfg.Metrics.Addr(),
+			Handler:           metricsMux,
+			ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		}),
+	)
+
+	// Only close the pool once no request can use it any more.
+	if closeErr := database.Close(); closeErr != nil {
+		log.Printf("Error closing database: %s", closeErr)
+	}
+	if err != nil {
+		log.Fatalf("Error serving: %s", err)
+	}
+
+	log.Println("Server stopped")
 }
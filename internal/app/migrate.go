@@ -0,0 +1,58 @@
+package app
+
+import (
+	"avito-internship/internal/config"
+	"avito-internship/internal/database"
+	"fmt"
+	"log"
+)
+
+// RunMigrations executes a single migration command against the configured
+// database and returns: "up" applies pending migrations, "down" rolls back the
+// latest one and "status" lists them all.
+func RunMigrations(db config.DatabaseConfig, command string) {
+	database.Connect(db)
+
+	migrator, err := database.NewMigrator(database.DB)
+	if err != nil {
+		log.Fatalf("Error loading migrations: %s", err)
+	}
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up()
+		if err != nil {
+			log.Fatalf("Error applying migrations: %s", err)
+		}
+		if len(applied) == 0 {
+			log.Println("Schema is up to date")
+		}
+		for _, m := range applied {
+			log.Printf("Applied %04d_%s", m.Version, m.Name)
+		}
+	case "down":
+		rolledBack, err := migrator.Down()
+		if err != nil {
+			log.Fatalf("Error rolling back migration: %s", err)
+		}
+		if rolledBack == nil {
+			log.Println("No migrations to roll back")
+			return
+		}
+		log.Printf("Rolled back %04d_%s", rolledBack.Version, rolledBack.Name)
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			log.Fatalf("Error reading migration status: %s", err)
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.AppliedAt != nil {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+			fmt.Printf("%04d_%-40s %s\n", s.Version, s.Name, state)
+		}
+	default:
+		log.Fatalf("Unknown migrate command %q, expected up, down or status", command)
+	}
+}
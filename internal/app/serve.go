@@ -0,0 +1,125 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// server is one listener the app runs until shutdown.
+type server struct {
+	name string
+	// serve blocks until the server stops.
+	serve func() error
+	// shutdown stops accepting work and waits for in-flight requests until
+	// ctx is done.
+	shutdown func(ctx context.Context) error
+}
+
+func httpServer(name string, srv *http.Server) server {
+	return server{
+		name: name,
+		serve: func() error {
+			if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		},
+		shutdown: srv.Shutdown,
+	}
+}
+
+func grpcServer(srv *grpc.Server, addr string) server {
+	return server{
+		name: "gRPC",
+		serve: func() error {
+			lis, err := net.Listen("tcp", addr)
+			if err != nil {
+				return err
+			}
+			return srv.Serve(lis)
+		},
+		shutdown: func(ctx context.Context) error {
+			done := make(chan struct{})
+			go func() {
+				srv.GracefulStop()
+				close(done)
+			}()
+
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				srv.Stop()
+				return ctx.Err()
+			}
+		},
+	}
+}
+
+// shutdownPlan describes how serve stops the servers.
+type shutdownPlan struct {
+	// drain, if set, is called as soon as shutdown begins, so that the
+	// readiness probe fails while the listeners are still open.
+	drain func()
+	// drainDelay is how long the servers keep accepting requests after
+	// drain, giving load balancers time to notice.
+	drainDelay time.Duration
+	// timeout bounds how long in-flight requests may run after that.
+	timeout time.Duration
+}
+
+// serve runs the servers until ctx is done or one of them fails, then shuts
+// all of them down as plan describes. It returns the error that stopped a
+// server, if any.
+func serve(ctx context.Context, plan shutdownPlan, servers ...server) error {
+	failed := make(chan error, len(servers))
+	for _, s := range servers {
+		go func(s server) {
+			if err := s.serve(); err != nil {
+				failed <- errors.New(s.name + ": " + err.Error())
+			}
+		}(s)
+	}
+
+	var cause error
+	select {
+	case <-ctx.Done():
+		log.Println("Shutting down, waiting for in-flight requests")
+	case cause = <-failed:
+		log.Printf("Shutting down after server error: %s", cause)
+	}
+
+	if plan.drain != nil {
+		plan.drain()
+	}
+	// A failed server cannot take traffic anyway, so only wait for load
+	// balancers on a regular shutdown.
+	if cause == nil && plan.drainDelay > 0 {
+		log.Printf("Draining for %s before closing listeners", plan.drainDelay)
+		time.Sleep(plan.drainDelay)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), plan.timeout)
+	defer cancel()
+
+	done := make(chan struct{}, len(servers))
+	for _, s := range servers {
+		go func(s server) {
+			if err := s.shutdown(shutdownCtx); err != nil {
+				log.Printf("Error shutting down %s server: %s", s.name, err)
+			}
+			done <- struct{}{}
+		}(s)
+	}
+	for range servers {
+		<-done
+	}
+
+	return cause
+}
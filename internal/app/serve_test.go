@@ -0,0 +1,158 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func freeAddr(t *testing.T) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer lis.Close()
+	return lis.Addr().String()
+}
+
+// waitListening polls until addr accepts connections.
+func waitListening(t *testing.T, addr string) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+		}
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestServe_DrainsInFlightRequests(t *testing.T) {
+	addr := freeAddr(t)
+	started := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		io.WriteString(w, "done")
+	})
+
+	ctx, stop := context.WithCancel(context.Background())
+	served := make(chan error)
+	go func() {
+		served <- serve(ctx, shutdownPlan{timeout: time.Second}, httpServer("HTTP", &http.Server{Addr: addr, Handler: mux}))
+	}()
+	waitListening(t, addr)
+
+	type result struct {
+		body string
+		err  error
+	}
+	response := make(chan result)
+	go func() {
+		resp, err := http.Get("http://" + addr + "/slow")
+		if err != nil {
+			response <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		response <- result{string(body), err}
+	}()
+
+	<-started
+	stop()
+
+	got := <-response
+	require.NoError(t, got.err)
+	assert.Equal(t, "done", got.body)
+	assert.NoError(t, <-served)
+
+	_, err := http.Get("http://" + addr + "/slow")
+	assert.Error(t, err, "server still accepts connections after shutdown")
+}
+
+func TestServe_ShutdownTimeout(t *testing.T) {
+	addr := freeAddr(t)
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/stuck", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+
+	ctx, stop := context.WithCancel(context.Background())
+	served := make(chan error)
+	go func() {
+		served <- serve(ctx, shutdownPlan{timeout: 50 * time.Millisecond}, httpServer("HTTP", &http.Server{Addr: addr, Handler: mux}))
+	}()
+	waitListening(t, addr)
+
+	go http.Get("http://" + addr + "/stuck")
+	<-started
+	stop()
+
+	select {
+	case err := <-served:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("serve did not give up on the stuck request")
+	}
+}
+
+func TestServe_ServerErrorStopsTheOthers(t *testing.T) {
+	shutDown := false
+	blocking := server{
+		name:  "blocking",
+		serve: func() error { select {} },
+		shutdown: func(context.Context) error {
+			shutDown = true
+			return nil
+		},
+	}
+	failing := server{
+		name:     "failing",
+		serve:    func() error { return errors.New("address in use") },
+		shutdown: func(context.Context) error { return nil },
+	}
+
+	err := serve(context.Background(), shutdownPlan{timeout: time.Second}, blocking, failing)
+	assert.EqualError(t, err, "failing: address in use")
+	assert.True(t, shutDown)
+}
+
+func TestServe_DrainsBeforeClosingListeners(t *testing.T) {
+	addr := freeAddr(t)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {})
+
+	draining := make(chan struct{})
+	plan := shutdownPlan{
+		drain:      func() { close(draining) },
+		drainDelay: 200 * time.Millisecond,
+		timeout:    time.Second,
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	served := make(chan error)
+	go func() { served <- serve(ctx, plan, httpServer("HTTP", &http.Server{Addr: addr, Handler: mux})) }()
+	waitListening(t, addr)
+
+	stop()
+	<-draining
+
+	resp, err := http.Get("http://" + addr + "/")
+	require.NoError(t, err, "server stopped accepting requests while draining")
+	resp.Body.Close()
+	assert.NoError(t, <-served)
+}
@@ -0,0 +1,99 @@
+package app
+
+import (
+	"avito-internship/internal/auth"
+	"avito-internship/internal/config"
+	"avito-internship/internal/database"
+	"avito-internship/internal/health"
+	"avito-internship/internal/repository"
+	"avito-internship/internal/repository/memory"
+	"avito-internship/internal/repository/postgres"
+	"avito-internship/internal/services"
+	"context"
+	"fmt"
+)
+
+type repositories struct {
+	users       repository.UserRepository
+	sessions    repository.SessionRepository
+	assignments repository.AssignmentRepository
+	pvz         repository.PVZRepository
+	receptions  repository.ReceptionRepository
+	products    repository.ProductRepository
+	audit       repository.AuditRepository
+}
+
+// NewServices builds the services on top of the given storage backend; dbCfg is
+// only used by the Postgres one. The memory backend needs no database and
+// loses its data on restart; it is meant for tests and local development.
+// Roles are checked against policy. The identities behind /dummyLogin tokens
+// are only created with dummyUsers.
+func NewServices(backend string, dbCfg config.DatabaseConfig, policy *auth.Policy, dummyUsers bool) (services.Services, error) {
+	var repos repositories
+
+	switch backend {
+	case config.StoragePostgres, "":
+		database.Connect(dbCfg)
+		database.Migrate()
+		if dummyUsers {
+			if err := database.SeedDummyUsers(context.Background(), database.DB); err != nil {
+				return services.Services{}, fmt.Errorf("seed dummy users: %w", err)
+			}
+		}
+
+		db := database.DB
+		repos = repositories{
+			users:       postgres.NewUserRepository(db, dbCfg.QueryTimeout),
+			sessions:    postgres.NewSessionRepository(db, dbCfg.QueryTimeout),
+			assignments: postgres.NewAssignmentRepository(db, dbCfg.QueryTimeout),
+			pvz:         postgres.NewPVZRepository(db, dbCfg.QueryTimeout),
+			receptions:  postgres.NewReceptionRepository(db, dbCfg.QueryTimeout),
+			products:    postgres.NewProductRepository(db, dbCfg.QueryTimeout),
+			audit:       postgres.NewAuditRepository(db, dbCfg.QueryTimeout),
+		}
+	case config.StorageMemory:
+		store := memory.NewStore()
+		if dummyUsers {
+			store.SeedDummyUsers()
+		}
+		repos = repositories{
+			users:       memory.NewUserRepository(store),
+			sessions:    memory.NewSessionRepository(store),
+			assignments: memory.NewAssignmentRepository(store),
+			pvz:         memory.NewPVZRepository(store),
+			receptions:  memory.NewReceptionRepository(store),
+			products:    memory.NewProductRepository(store),
+			audit:       memory.NewAuditRepository(store),
+		}
+	default:
+		return services.Services{}, fmt.Errorf("unknown storage backend %q", backend)
+	}
+
+	return services.Services{
+		Users:       services.NewUserService(repos.users, policy),
+		Sessions:    services.NewSessionService(repos.sessions),
+		Assignments: services.NewAssignmentService(repos.users, repos.assignments, policy),
+		PVZ:         services.NewPVZService(repos.pvz, repos.receptions, repos.products),
+		Receptions:  services.NewReceptionService(repos.receptions),
+		Products:    services.NewProductService(repos.products),
+		Audit:       services.NewAuditService(repos.audit),
+	}, nil
+}
+
+// readinessChecks are the dependency checks behind /readyz for the storage
+// backend. The memory backend has none.
+func readinessChecks(backend string) ([]health.Check, error) {
+	if backend == config.StorageMemory {
+		return nil, nil
+	}
+
+	migrator, err := database.NewMigrator(database.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	return []health.Check{
+		{Name: "database", Run: database.DB.PingContext},
+		{Name: "migrations", Run: migrator.CheckApplied},
+	}, nil
+}
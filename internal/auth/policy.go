@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+const (
+	RoleEmployee  = "employee"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+type Action string
+
+const (
+	ActionCreatePVZ       Action = "pvz:create"
+	ActionListPVZ         Action = "pvz:list"
+	ActionManageEmployees Action = "pvz:manage_employees"
+	ActionCreateReception Action = "reception:create"
+	ActionCloseReception  Action = "reception:close"
+	ActionAddProduct      Action = "product:add"
+	ActionDeleteProduct   Action = "product:delete"
+	ActionRevokeSessions  Action = "session:revoke"
+	ActionViewAudit       Action = "audit:view"
+	ActionCreateUser      Action = "user:create"
+)
+
+var knownActions = map[Action]bool{
+	ActionCreatePVZ: true, ActionListPVZ: true, ActionManageEmployees: true,
+	ActionCreateReception: true, ActionCloseReception: true, ActionAddProduct: true,
+	ActionDeleteProduct: true, ActionRevokeSessions: true, ActionViewAudit: true,
+	ActionCreateUser: true,
+}
+
+// PolicyConfig is the declarative form of a Policy, as stored in the file
+// referenced by RBAC_POLICY_FILE.
+type PolicyConfig struct {
+	// Permissions lists the roles allowed to perform each action.
+	Permissions map[Action][]string `json:"permissions"`
+	// PVZScopedRoles may act only on PVZs they are assigned to.
+	PVZScopedRoles []string `json:"pvzScopedRoles"`
+}
+
+var DefaultPolicyConfig = PolicyConfig{
+	Permissions: map[Action][]string{
+		ActionCreatePVZ:       {RoleModerator, RoleAdmin},
+		ActionListPVZ:         {RoleEmployee, RoleModerator, RoleAdmin},
+		ActionManageEmployees: {RoleModerator, RoleAdmin},
+		ActionCreateReception: {RoleEmployee, RoleAdmin},
+		ActionCloseReception:  {RoleEmployee, RoleAdmin},
+		ActionAddProduct:      {RoleEmployee, RoleAdmin},
+		ActionDeleteProduct:   {RoleEmployee, RoleAdmin},
+		ActionRevokeSessions:  {RoleModerator, RoleAdmin},
+		ActionViewAudit:       {RoleModerator, RoleAdmin},
+		ActionCreateUser:      {RoleAdmin},
+	},
+	PVZScopedRoles: []string{RoleEmployee},
+}
+
+// Validate rejects unknown actions, empty role names and PVZ-scoped roles
+// that are granted no action, which are most likely typos.
+func (c PolicyConfig) Validate() error {
+	var errs []error
+	roles := map[string]bool{}
+	for action, actionRoles := range c.Permissions {
+		if !knownActions[action] {
+			errs = append(errs, fmt.Errorf("unknown action %q", action))
+		}
+		for _, role := range actionRoles {
+			if role == "" {
+				errs = append(errs, fmt.Errorf("empty role in %q", action))
+			}
+			roles[role] = true
+		}
+	}
+
+	for _, role := range c.PVZScopedRoles {
+		if !roles[role] {
+			errs = append(errs, fmt.Errorf("pvzScopedRoles: role %q is granted no action", role))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+type Policy struct {
+	permissions map[Action]map[string]bool
+	roles       map[string]bool
+	pvzScoped   map[string]bool
+}
+
+func NewPolicy(cfg PolicyConfig) *Policy {
+	p := &Policy{
+		permissions: make(map[Action]map[string]bool),
+		roles:       make(map[string]bool),
+		pvzScoped:   make(map[string]bool),
+	}
+
+	for action, roles := range cfg.Permissions {
+		p.permissions[action] = make(map[string]bool)
+		for _, role := range roles {
+			p.permissions[action][role] = true
+			p.roles[role] = true
+		}
+	}
+
+	for _, role := range cfg.PVZScopedRoles {
+		p.pvzScoped[role] = true
+	}
+
+	return p
+}
+
+// LoadPolicy loads the permission matrix from a JSON file, falling back to
+// DefaultPolicyConfig when path is empty.
+func LoadPolicy(path string) (*Policy, error) {
+	if path == "" {
+		return NewPolicy(DefaultPolicyConfig), nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg PolicyConfig
+	if err := json.Unmarshal(content, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", path, err)
+	}
+
+	return NewPolicy(cfg), nil
+}
+
+func (p *Policy) Allows(role string, action Action) bool {
+	return p.permissions[action][role]
+}
+
+// HasRole reports whether the role is granted at least one action.
+func (p *Policy) HasRole(role string) bool {
+	return p.roles[role]
+}
+
+func (p *Policy) IsPVZScoped(role string) bool {
+	return p.pvzScoped[role]
+}
@@ -0,0 +1,71 @@
+package auth_test
+
+import (
+	"avito-internship/internal/auth"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadPolicy_Default(t *testing.T) {
+	policy, err := auth.LoadPolicy("")
+	require.NoError(t, err)
+
+	assert.True(t, policy.Allows(auth.RoleModerator, auth.ActionCreatePVZ))
+	assert.False(t, policy.Allows(auth.RoleEmployee, auth.ActionCreatePVZ))
+	assert.True(t, policy.HasRole(auth.RoleAdmin))
+	assert.True(t, policy.IsPVZScoped(auth.RoleEmployee))
+	assert.False(t, policy.IsPVZScoped(auth.RoleAdmin))
+}
+
+func TestLoadPolicy_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	content := `{
+		"permissions": {
+			"reception:close": ["employee", "supervisor"],
+			"pvz:list": ["supervisor"]
+		},
+		"pvzScopedRoles": ["employee"]
+	}`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	policy, err := auth.LoadPolicy(path)
+	require.NoError(t, err)
+
+	assert.True(t, policy.HasRole("supervisor"))
+	assert.True(t, policy.Allows("supervisor", auth.ActionCloseReception))
+	assert.False(t, policy.Allows(auth.RoleModerator, auth.ActionCreatePVZ))
+}
+
+func TestLoadPolicy_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+
+	_, err := auth.LoadPolicy(path)
+	assert.Error(t, err)
+}
+
+func TestLoadPolicy_RejectsTypos(t *testing.T) {
+	cases := map[string]string{
+		"unknown action": `{"permissions": {"reception:open": ["employee"]}}`,
+		"empty role":     `{"permissions": {"reception:close": ["employee", ""]}}`,
+		"unknown scoped": `{"permissions": {"reception:close": ["employee"]}, "pvzScopedRoles": ["employe"]}`,
+	}
+
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "policy.json")
+			require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+			_, err := auth.LoadPolicy(path)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDefaultPolicyConfig_Valid(t *testing.T) {
+	assert.NoError(t, auth.DefaultPolicyConfig.Validate())
+}
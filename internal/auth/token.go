@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Identities carried by tokens issued through /dummyLogin. When dummy login is
+// enabled, matching rows are seeded in the users table so dev accounts can be
+// assigned to PVZs, but no password ever matches them.
+const (
+	DummyEmployeeID  = "00000000-0000-0000-0000-000000000001"
+	DummyModeratorID = "00000000-0000-0000-0000-000000000002"
+	DummyAdminID     = "00000000-0000-0000-0000-000000000003"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// Claims carry the session a token was issued for in "sid". Tokens from
+// /dummyLogin have no session and therefore cannot be revoked.
+type Claims struct {
+	Role      string `json:"role"`
+	SessionID string `json:"sid,omitempty"`
+	jwt.RegisteredClaims
+}
+
+func (c *Claims) UserID() string {
+	return c.Subject
+}
+
+// TokenManager signs access tokens with the active key and verifies them with
+// any known key, selected by the "kid" header. Keeping retired keys in the set
+// lets tokens issued before a rotation stay valid until they expire.
+type TokenManager struct {
+	keys        map[string][]byte
+	activeKeyID string
+	ttl         time.Duration
+	refreshTTL  time.Duration
+	now         func() time.Time
+}
+
+func NewTokenManager(keys map[string][]byte, activeKeyID string, ttl, refreshTTL time.Duration) (*TokenManager, error) {
+	if len(keys[activeKeyID]) == 0 {
+		return nil, fmt.Errorf("signing key %q is not configured", activeKeyID)
+	}
+	if ttl <= 0 || refreshTTL <= 0 {
+		return nil, errors.New("token ttl must be positive")
+	}
+
+	return &TokenManager{
+		keys:        keys,
+		activeKeyID: activeKeyID,
+		ttl:         ttl,
+		refreshTTL:  refreshTTL,
+		now:         time.Now,
+	}, nil
+}
+
+// RefreshTTL is how long a session may be refreshed before the user has to
+// log in again.
+func (m *TokenManager) RefreshTTL() time.Duration {
+	return m.refreshTTL
+}
+
+func (m *TokenManager) Issue(userID, role, sessionID string) (string, error) {
+	now := m.now()
+	claims := Claims{
+		Role:      role,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.ttl)),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = m.activeKeyID
+	return token.SignedString(m.keys[m.activeKeyID])
+}
+
+func (m *TokenManager) Parse(tokenString string) (*Claims, error) {
+	var claims Claims
+	_, err := jwt.ParseWithClaims(tokenString, &claims, m.keyFor,
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(m.now),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	return &claims, nil
+}
+
+func (m *TokenManager) keyFor(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := m.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	return key, nil
+}
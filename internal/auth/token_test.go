@@ -0,0 +1,100 @@
+package auth_test
+
+import (
+	"avito-internship/internal/auth"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenManager_IssueAndParse(t *testing.T) {
+	tokens, err := auth.NewTokenManager(map[string][]byte{"k1": []byte("secret")}, "k1", time.Hour, time.Hour)
+	require.NoError(t, err)
+
+	token, err := tokens.Issue("user-1", "employee", "")
+	require.NoError(t, err)
+
+	claims, err := tokens.Parse(token)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.UserID())
+	assert.Equal(t, "employee", claims.Role)
+}
+
+func TestTokenManager_Rotation(t *testing.T) {
+	old, err := auth.NewTokenManager(map[string][]byte{"k1": []byte("old")}, "k1", time.Hour, time.Hour)
+	require.NoError(t, err)
+	oldToken, err := old.Issue("user-1", "moderator", "")
+	require.NoError(t, err)
+
+	rotated, err := auth.NewTokenManager(map[string][]byte{
+		"k1": []byte("old"),
+		"k2": []byte("new"),
+	}, "k2", time.Hour, time.Hour)
+	require.NoError(t, err)
+
+	_, err = rotated.Parse(oldToken)
+	assert.NoError(t, err)
+
+	newToken, err := rotated.Issue("user-1", "moderator", "")
+	require.NoError(t, err)
+	_, err = old.Parse(newToken)
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+}
+
+func TestTokenManager_Expired(t *testing.T) {
+	tokens, err := auth.NewTokenManager(map[string][]byte{"k1": []byte("secret")}, "k1", time.Hour, time.Hour)
+	require.NoError(t, err)
+
+	expired := jwt.NewWithClaims(jwt.SigningMethodHS256, auth.Claims{
+		Role: "employee",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "user-1",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+		},
+	})
+	expired.Header["kid"] = "k1"
+	token, err := expired.SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	_, err = tokens.Parse(token)
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+}
+
+func TestTokenManager_Tampered(t *testing.T) {
+	tokens, err := auth.NewTokenManager(map[string][]byte{"k1": []byte("secret")}, "k1", time.Hour, time.Hour)
+	require.NoError(t, err)
+
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, auth.Claims{
+		Role: "moderator",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "user-1",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	forged.Header["kid"] = "k1"
+	token, err := forged.SignedString([]byte("guessed"))
+	require.NoError(t, err)
+
+	_, err = tokens.Parse(token)
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	_, err = tokens.Parse("moderator")
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+}
+
+func TestNewTokenManager_UnknownActiveKey(t *testing.T) {
+	_, err := auth.NewTokenManager(map[string][]byte{"k1": []byte("secret")}, "k2", time.Hour, time.Hour)
+	assert.Error(t, err)
+}
+
+func TestNewRefreshToken(t *testing.T) {
+	token, hash, err := auth.NewRefreshToken()
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, token)
+	assert.NotEqual(t, token, hash)
+	assert.Equal(t, hash, auth.HashRefreshToken(token))
+}
@@ -0,0 +1,344 @@
+// Package config loads the service settings from an optional YAML file and
+// environment variables, which take precedence over the file.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+
+	EnvProduction = "production"
+)
+
+type Config struct {
+	// Env is "production" or anything else; production refuses to start
+	// with auth.dummyLogin enabled.
+	Env        string           `yaml:"env"`
+	Storage    string           `yaml:"storage"`
+	HTTP       HTTPConfig       `yaml:"http"`
+	GRPC       ServerConfig     `yaml:"grpc"`
+	Metrics    ServerConfig     `yaml:"metrics"`
+	Database   DatabaseConfig   `yaml:"database"`
+	Auth       AuthConfig       `yaml:"auth"`
+	Pagination PaginationConfig `yaml:"pagination"`
+	// DrainDelay is how long the servers keep accepting requests after
+	// SIGTERM while /readyz already reports the instance as draining.
+	DrainDelay time.Duration `yaml:"drainDelay"`
+	// ShutdownTimeout bounds how long in-flight requests may run after
+	// SIGTERM before the servers are stopped.
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout"`
+	// ReadinessTimeout bounds the dependency checks of one /readyz request.
+	ReadinessTimeout time.Duration `yaml:"readinessTimeout"`
+}
+
+type ServerConfig struct {
+	Port int `yaml:"port"`
+}
+
+// Addr is the listen address of the server.
+func (c ServerConfig) Addr() string {
+	return fmt.Sprintf(":%d", c.Port)
+}
+
+// HTTPConfig holds the timeouts of the API server, see http.Server.
+type HTTPConfig struct {
+	ServerConfig      `yaml:",inline"`
+	ReadHeaderTimeout time.Duration `yaml:"readHeaderTimeout"`
+	ReadTimeout       time.Duration `yaml:"readTimeout"`
+	WriteTimeout      time.Duration `yaml:"writeTimeout"`
+	IdleTimeout       time.Duration `yaml:"idleTimeout"`
+}
+
+// DatabaseConfig describes the Postgres connection. A non-empty DSN is used
+// as is; otherwise one is built from the individual fields.
+type DatabaseConfig struct {
+	DSN      string `yaml:"dsn"`
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Name     string `yaml:"name"`
+	// SSLMode is a libpq sslmode, from "disable" to "verify-full".
+	SSLMode string `yaml:"sslMode"`
+
+	MaxOpenConns    int           `yaml:"maxOpenConns"`
+	MaxIdleConns    int           `yaml:"maxIdleConns"`
+	ConnMaxLifetime time.Duration `yaml:"connMaxLifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"connMaxIdleTime"`
+
+	// QueryTimeout bounds every query, and every transaction as a whole,
+	// even if the client waits longer. Zero disables the limit.
+	QueryTimeout time.Duration `yaml:"queryTimeout"`
+}
+
+// ConnString returns the DSN, or builds one in libpq key/value form.
+func (c DatabaseConfig) ConnString() string {
+	if c.DSN != "" {
+		return c.DSN
+	}
+
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		c.Host, c.Port, c.User, c.Password, c.Name, c.SSLMode)
+}
+
+// SigningKey is a JWT signing secret and the key id tokens name it by.
+type SigningKey struct {
+	ID     string `yaml:"id"`
+	Secret string `yaml:"secret"`
+}
+
+type AuthConfig struct {
+	Keys []SigningKey `yaml:"keys"`
+	// ActiveKey signs new tokens; the first key when empty. The others
+	// are only accepted, so that keys can be rotated.
+	ActiveKey  string        `yaml:"activeKey"`
+	TokenTTL   time.Duration `yaml:"tokenTTL"`
+	RefreshTTL time.Duration `yaml:"refreshTTL"`
+	// PolicyFile is a JSON auth.PolicyConfig; the default policy applies
+	// when empty.
+	PolicyFile string `yaml:"policyFile"`
+	// DummyLogin routes /dummyLogin, which issues tokens without
+	// credentials. Meant for local development only.
+	DummyLogin bool `yaml:"dummyLogin"`
+}
+
+// KeyMap returns the signing secrets by key id.
+func (c AuthConfig) KeyMap() map[string][]byte {
+	keys := make(map[string][]byte, len(c.Keys))
+	for _, k := range c.Keys {
+		keys[k.ID] = []byte(k.Secret)
+	}
+	return keys
+}
+
+type PaginationConfig struct {
+	// DefaultLimit and MaxLimit apply to GET /pvz; larger limits are capped.
+	DefaultLimit int `yaml:"defaultLimit"`
+	MaxLimit     int `yaml:"maxLimit"`
+	// GRPCDefaultPageSize applies to GetPVZList requests without a page
+	// size; GRPCMaxPageSize caps larger ones.
+	GRPCDefaultPageSize int `yaml:"grpcDefaultPageSize"`
+	GRPCMaxPageSize     int `yaml:"grpcMaxPageSize"`
+}
+
+// Default returns the settings used for anything the file and the
+// environment leave unset.
+func Default() Config {
+	return Config{
+		Storage: StoragePostgres,
+		HTTP: HTTPConfig{
+			ServerConfig:      ServerConfig{Port: 8080},
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       15 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       2 * time.Minute,
+		},
+		GRPC:    ServerConfig{Port: 3000},
+		Metrics: ServerConfig{Port: 9000},
+		Database: DatabaseConfig{
+			Host:            "localhost",
+			Port:            5432,
+			SSLMode:         "disable",
+			MaxOpenConns:    20,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+			QueryTimeout:    5 * time.Second,
+		},
+		Auth: AuthConfig{
+			TokenTTL:   12 * time.Hour,
+			RefreshTTL: 7 * 24 * time.Hour,
+		},
+		Pagination: PaginationConfig{
+			DefaultLimit:        10,
+			MaxLimit:            30,
+			GRPCDefaultPageSize: 20,
+			GRPCMaxPageSize:     100,
+		},
+		DrainDelay:       5 * time.Second,
+		ShutdownTimeout:  20 * time.Second,
+		ReadinessTimeout: 2 * time.Second,
+	}
+}
+
+// Load reads the YAML file at path, if path is not empty, on top of the
+// defaults, applies the environment overrides and validates the result.
+func Load(path string) (*Config, error) {
+	cfg, err := read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// LoadDatabase is Load for commands that only talk to the database, such as
+// schema migrations: it validates the database section alone, so that they
+// run without the auth keys and the other settings of the server.
+func LoadDatabase(path string) (*DatabaseConfig, error) {
+	cfg, err := read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Database.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &cfg.Database, nil
+}
+
+func read(path string) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := yaml.Unmarshal(content, &cfg); err != nil {
+			return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+	}
+
+	if err := applyEnv(&cfg, os.LookupEnv); err != nil {
+		return nil, err
+	}
+	if cfg.Auth.ActiveKey == "" && len(cfg.Auth.Keys) > 0 {
+		cfg.Auth.ActiveKey = cfg.Auth.Keys[0].ID
+	}
+
+	return &cfg, nil
+}
+
+var sslModes = map[string]bool{
+	"disable": true, "allow": true, "prefer": true, "require": true, "verify-ca": true, "verify-full": true,
+}
+
+// Validate reports every invalid setting of the database section at once.
+func (c DatabaseConfig) Validate() error {
+	var errs []error
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.DSN == "" {
+		if c.Host == "" || c.User == "" || c.Name == "" {
+			fail("database: dsn or host, user and name are required")
+		}
+		if !sslModes[c.SSLMode] {
+			fail("database.sslMode: unknown mode %q", c.SSLMode)
+		}
+	}
+	if c.MaxOpenConns < 0 || c.MaxIdleConns < 0 {
+		fail("database: connection pool sizes must not be negative")
+	}
+	if c.MaxOpenConns > 0 && c.MaxIdleConns > c.MaxOpenConns {
+		fail("database.maxIdleConns: must not exceed maxOpenConns")
+	}
+	if c.QueryTimeout < 0 {
+		fail("database.queryTimeout: must not be negative")
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.Storage != StoragePostgres && c.Storage != StorageMemory {
+		fail("storage: must be %s or %s, got %q", StoragePostgres, StorageMemory, c.Storage)
+	}
+
+	ports := map[int]string{}
+	for _, s := range []struct {
+		name string
+		cfg  ServerConfig
+	}{{"http", c.HTTP.ServerConfig}, {"grpc", c.GRPC}, {"metrics", c.Metrics}} {
+		if s.cfg.Port < 1 || s.cfg.Port > 65535 {
+			fail("%s.port: must be between 1 and 65535, got %d", s.name, s.cfg.Port)
+			continue
+		}
+		if other, taken := ports[s.cfg.Port]; taken {
+			fail("%s.port: %d is already used by %s", s.name, s.cfg.Port, other)
+		}
+		ports[s.cfg.Port] = s.name
+	}
+
+	if c.Storage == StoragePostgres {
+		if err := c.Database.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	keyIDs := map[string]bool{}
+	for _, k := range c.Auth.Keys {
+		if k.ID == "" || k.Secret == "" {
+			fail("auth.keys: every key needs an id and a secret")
+			continue
+		}
+		if keyIDs[k.ID] {
+			fail("auth.keys: duplicate key id %q", k.ID)
+		}
+		keyIDs[k.ID] = true
+	}
+	if len(c.Auth.Keys) == 0 {
+		fail("auth.keys: at least one signing key is required")
+	}
+	if c.Auth.ActiveKey != "" && len(keyIDs) > 0 && !keyIDs[c.Auth.ActiveKey] {
+		fail("auth.activeKey: unknown key id %q", c.Auth.ActiveKey)
+	}
+	if c.Auth.DummyLogin && c.Env == EnvProduction {
+		fail("auth.dummyLogin: must not be enabled in production")
+	}
+	if c.Auth.TokenTTL <= 0 || c.Auth.RefreshTTL <= 0 {
+		fail("auth: token TTLs must be positive")
+	}
+
+	h := c.HTTP
+	if h.ReadHeaderTimeout < 0 || h.ReadTimeout < 0 || h.WriteTimeout < 0 || h.IdleTimeout < 0 {
+		fail("http: timeouts must not be negative")
+	}
+	if c.DrainDelay < 0 {
+		fail("drainDelay: must not be negative")
+	}
+	if c.ShutdownTimeout <= 0 {
+		fail("shutdownTimeout: must be positive")
+	}
+	if c.ReadinessTimeout <= 0 {
+		fail("readinessTimeout: must be positive")
+	}
+
+	p := c.Pagination
+	if p.DefaultLimit < 1 {
+		fail("pagination.defaultLimit: must be at least 1")
+	}
+	if p.MaxLimit < p.DefaultLimit {
+		fail("pagination.maxLimit: must not be below defaultLimit")
+	}
+	if p.GRPCDefaultPageSize < 1 {
+		fail("pagination.grpcDefaultPageSize: must be at least 1")
+	}
+	if p.GRPCMaxPageSize < p.GRPCDefaultPageSize {
+		fail("pagination.grpcMaxPageSize: must not be below grpcDefaultPageSize")
+	}
+
+	return errors.Join(errs...)
+}
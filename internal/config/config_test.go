@@ -0,0 +1,194 @@
+package config_test
+
+import (
+	"avito-internship/internal/config"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clearEnv unsets the variables the tests below rely on, so that the
+// environment of the test run does not leak in.
+func clearEnv(t *testing.T) {
+	for _, name := range []string{
+		"APP_ENV", "STORAGE_BACKEND", "HTTP_PORT", "GRPC_PORT", "METRICS_PORT",
+		"DB_DSN", "DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD", "DB_NAME", "DB_SSLMODE", "DB_QUERY_TIMEOUT",
+		"JWT_KEYS", "JWT_ACTIVE_KEY", "JWT_TTL", "JWT_REFRESH_TTL", "PAGE_MAX_LIMIT", "SHUTDOWN_TIMEOUT", "DRAIN_DELAY",
+		"DUMMY_LOGIN_ENABLED", "GRPC_DEFAULT_PAGE_SIZE", "GRPC_MAX_PAGE_SIZE", "READINESS_TIMEOUT",
+	} {
+		t.Setenv(name, "")
+	}
+}
+
+func writeFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_EnvOnly(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("DB_HOST", "db")
+	t.Setenv("DB_USER", "postgres")
+	t.Setenv("DB_NAME", "pvzdb")
+	t.Setenv("JWT_KEYS", "k1:one, k2:two")
+	t.Setenv("JWT_TTL", "30m")
+
+	cfg, err := config.Load("")
+	require.NoError(t, err)
+
+	assert.Equal(t, ":8080", cfg.HTTP.Addr())
+	assert.Equal(t, ":3000", cfg.GRPC.Addr())
+	assert.Equal(t, ":9000", cfg.Metrics.Addr())
+	assert.Equal(t, "host=db port=5432 user=postgres password= dbname=pvzdb sslmode=disable", cfg.Database.ConnString())
+	assert.Equal(t, map[string][]byte{"k1": []byte("one"), "k2": []byte("two")}, cfg.Auth.KeyMap())
+	assert.Equal(t, "k1", cfg.Auth.ActiveKey)
+	assert.Equal(t, 30*time.Minute, cfg.Auth.TokenTTL)
+	assert.Equal(t, 30, cfg.Pagination.MaxLimit)
+	assert.False(t, cfg.Auth.DummyLogin)
+}
+
+func TestLoad_FileWithEnvOverrides(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, `
+env: production
+storage: postgres
+http:
+  port: 8081
+database:
+  dsn: postgres://app@db/pvz?sslmode=verify-full
+  maxOpenConns: 50
+  connMaxLifetime: 1h
+auth:
+  keys:
+    - id: old
+      secret: s1
+    - id: new
+      secret: s2
+  activeKey: new
+  refreshTTL: 48h
+pagination:
+  maxLimit: 50
+`)
+	t.Setenv("HTTP_PORT", "9090")
+	t.Setenv("PAGE_MAX_LIMIT", "40")
+	t.Setenv("DB_QUERY_TIMEOUT", "2s")
+
+	cfg, err := config.Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, config.EnvProduction, cfg.Env)
+	assert.Equal(t, 9090, cfg.HTTP.Port)
+	assert.Equal(t, "postgres://app@db/pvz?sslmode=verify-full", cfg.Database.ConnString())
+	assert.Equal(t, 50, cfg.Database.MaxOpenConns)
+	assert.Equal(t, 10, cfg.Database.MaxIdleConns)
+	assert.Equal(t, time.Hour, cfg.Database.ConnMaxLifetime)
+	assert.Equal(t, 2*time.Second, cfg.Database.QueryTimeout)
+	assert.Equal(t, "new", cfg.Auth.ActiveKey)
+	assert.Equal(t, 48*time.Hour, cfg.Auth.RefreshTTL)
+	assert.Equal(t, 12*time.Hour, cfg.Auth.TokenTTL)
+	assert.Equal(t, 40, cfg.Pagination.MaxLimit)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	cases := map[string]struct {
+		env  map[string]string
+		file string
+		want string
+	}{
+		"no keys":           {want: "auth.keys: at least one signing key is required"},
+		"malformed keys":    {env: map[string]string{"JWT_KEYS": "no-secret"}, want: "invalid JWT_KEYS"},
+		"bad port":          {env: map[string]string{"GRPC_PORT": "three thousand"}, want: "invalid GRPC_PORT"},
+		"shared port":       {env: map[string]string{"METRICS_PORT": "8080"}, want: "metrics.port: 8080 is already used by http"},
+		"unknown storage":   {env: map[string]string{"STORAGE_BACKEND": "mongo"}, want: `storage: must be postgres or memory, got "mongo"`},
+		"unknown ssl mode":  {env: map[string]string{"DB_SSLMODE": "sometimes"}, want: `database.sslMode: unknown mode "sometimes"`},
+		"query timeout":     {env: map[string]string{"DB_QUERY_TIMEOUT": "-1s"}, want: "database.queryTimeout: must not be negative"},
+		"unknown key":       {env: map[string]string{"JWT_ACTIVE_KEY": "k9"}, want: `auth.activeKey: unknown key id "k9"`},
+		"page limits":       {file: "pagination: {defaultLimit: 20, maxLimit: 10}", want: "pagination.maxLimit: must not be below defaultLimit"},
+		"grpc page sizes":   {env: map[string]string{"GRPC_DEFAULT_PAGE_SIZE": "50", "GRPC_MAX_PAGE_SIZE": "20"}, want: "pagination.grpcMaxPageSize: must not be below grpcDefaultPageSize"},
+		"malformed file":    {file: "http: [8080]", want: "invalid config file"},
+		"readiness timeout": {env: map[string]string{"READINESS_TIMEOUT": "0s"}, want: "readinessTimeout: must be positive"},
+		"dummy login in production": {
+			env:  map[string]string{"APP_ENV": "production", "DUMMY_LOGIN_ENABLED": "true"},
+			want: "auth.dummyLogin: must not be enabled in production",
+		},
+		"malformed dummy login": {env: map[string]string{"DUMMY_LOGIN_ENABLED": "sure"}, want: "invalid DUMMY_LOGIN_ENABLED"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			clearEnv(t)
+			t.Setenv("DB_USER", "postgres")
+			t.Setenv("DB_NAME", "pvzdb")
+			if name != "no keys" {
+				t.Setenv("JWT_KEYS", "k1:one")
+			}
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+
+			path := ""
+			if tc.file != "" {
+				path = writeFile(t, tc.file)
+			}
+
+			_, err := config.Load(path)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.want)
+		})
+	}
+}
+
+func TestLoad_MemoryStorageNeedsNoDatabase(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("STORAGE_BACKEND", config.StorageMemory)
+	t.Setenv("JWT_KEYS", "k1:one")
+
+	_, err := config.Load("")
+	assert.NoError(t, err)
+}
+
+func TestLoadDatabase_ValidatesOnlyTheDatabase(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("DB_USER", "postgres")
+	t.Setenv("DB_NAME", "pvzdb")
+	t.Setenv("READINESS_TIMEOUT", "0s")
+
+	// Neither the missing keys nor the bad timeout matter to migrations.
+	db, err := config.LoadDatabase("")
+	require.NoError(t, err)
+	assert.Equal(t, "pvzdb", db.Name)
+
+	t.Setenv("DB_SSLMODE", "sometimes")
+	_, err = config.LoadDatabase("")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `database.sslMode: unknown mode "sometimes"`)
+}
+
+func TestLoad_HTTPTimeouts(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("STORAGE_BACKEND", config.StorageMemory)
+	t.Setenv("JWT_KEYS", "k1:one")
+	t.Setenv("SHUTDOWN_TIMEOUT", "45s")
+	t.Setenv("DRAIN_DELAY", "0s")
+	t.Setenv("READINESS_TIMEOUT", "500ms")
+	path := writeFile(t, `
+http:
+  port: 8081
+  writeTimeout: 1m
+`)
+
+	cfg, err := config.Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, 8081, cfg.HTTP.Port)
+	assert.Equal(t, time.Minute, cfg.HTTP.WriteTimeout)
+	assert.Equal(t, 5*time.Second, cfg.HTTP.ReadHeaderTimeout)
+	assert.Equal(t, 45*time.Second, cfg.ShutdownTimeout)
+	assert.Zero(t, cfg.DrainDelay)
+	assert.Equal(t, 500*time.Millisecond, cfg.ReadinessTimeout)
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// envOverrides maps environment variables to the setting they override.
+// The names predate the config file and are kept for existing deployments.
+var envOverrides = map[string]func(c *Config, v string) error{
+	"APP_ENV":         func(c *Config, v string) error { c.Env = v; return nil },
+	"STORAGE_BACKEND": func(c *Config, v string) error { c.Storage = v; return nil },
+
+	"HTTP_PORT":    intSetter(func(c *Config) *int { return &c.HTTP.Port }),
+	"GRPC_PORT":    intSetter(func(c *Config) *int { return &c.GRPC.Port }),
+	"METRICS_PORT": intSetter(func(c *Config) *int { return &c.Metrics.Port }),
+
+	"DB_DSN":                func(c *Config, v string) error { c.Database.DSN = v; return nil },
+	"DB_HOST":               func(c *Config, v string) error { c.Database.Host = v; return nil },
+	"DB_PORT":               intSetter(func(c *Config) *int { return &c.Database.Port }),
+	"DB_USER":               func(c *Config, v string) error { c.Database.User = v; return nil },
+	"DB_PASSWORD":           func(c *Config, v string) error { c.Database.Password = v; return nil },
+	"DB_NAME":               func(c *Config, v string) error { c.Database.Name = v; return nil },
+	"DB_SSLMODE":            func(c *Config, v string) error { c.Database.SSLMode = v; return nil },
+	"DB_MAX_OPEN_CONNS":     intSetter(func(c *Config) *int { return &c.Database.MaxOpenConns }),
+	"DB_MAX_IDLE_CONNS":     intSetter(func(c *Config) *int { return &c.Database.MaxIdleConns }),
+	"DB_CONN_MAX_LIFETIME":  durationSetter(func(c *Config) *time.Duration { return &c.Database.ConnMaxLifetime }),
+	"DB_CONN_MAX_IDLE_TIME": durationSetter(func(c *Config) *time.Duration { return &c.Database.ConnMaxIdleTime }),
+	"DB_QUERY_TIMEOUT":      durationSetter(func(c *Config) *time.Duration { return &c.Database.QueryTimeout }),
+
+	"JWT_KEYS":            setKeys,
+	"JWT_ACTIVE_KEY":      func(c *Config, v string) error { c.Auth.ActiveKey = v; return nil },
+	"JWT_TTL":             durationSetter(func(c *Config) *time.Duration { return &c.Auth.TokenTTL }),
+	"JWT_REFRESH_TTL":     durationSetter(func(c *Config) *time.Duration { return &c.Auth.RefreshTTL }),
+	"RBAC_POLICY_FILE":    func(c *Config, v string) error { c.Auth.PolicyFile = v; return nil },
+	"DUMMY_LOGIN_ENABLED": boolSetter(func(c *Config) *bool { return &c.Auth.DummyLogin }),
+
+	"PAGE_DEFAULT_LIMIT":     intSetter(func(c *Config) *int { return &c.Pagination.DefaultLimit }),
+	"PAGE_MAX_LIMIT":         intSetter(func(c *Config) *int { return &c.Pagination.MaxLimit }),
+	"GRPC_DEFAULT_PAGE_SIZE": intSetter(func(c *Config) *int { return &c.Pagination.GRPCDefaultPageSize }),
+	"GRPC_MAX_PAGE_SIZE":     intSetter(func(c *Config) *int { return &c.Pagination.GRPCMaxPageSize }),
+
+	"HTTP_READ_HEADER_TIMEOUT": durationSetter(func(c *Config) *time.Duration { return &c.HTTP.ReadHeaderTimeout }),
+	"HTTP_READ_TIMEOUT":        durationSetter(func(c *Config) *time.Duration { return &c.HTTP.ReadTimeout }),
+	"HTTP_WRITE_TIMEOUT":       durationSetter(func(c *Config) *time.Duration { return &c.HTTP.WriteTimeout }),
+	"HTTP_IDLE_TIMEOUT":        durationSetter(func(c *Config) *time.Duration { return &c.HTTP.IdleTimeout }),
+	"DRAIN_DELAY":              durationSetter(func(c *Config) *time.Duration { return &c.DrainDelay }),
+	"SHUTDOWN_TIMEOUT":         durationSetter(func(c *Config) *time.Duration { return &c.ShutdownTimeout }),
+	"READINESS_TIMEOUT":        durationSetter(func(c *Config) *time.Duration { return &c.ReadinessTimeout }),
+}
+
+// applyEnv applies the overrides of the variables that are set and not empty.
+func applyEnv(c *Config, lookup func(string) (string, bool)) error {
+	var errs []error
+	for name, set := range envOverrides {
+		v, ok := lookup(name)
+		if !ok || v == "" {
+			continue
+		}
+		if err := set(c, v); err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func intSetter(field func(*Config) *int) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return err
+		}
+		*field(c) = n
+		return nil
+	}
+}
+
+func boolSetter(field func(*Config) *bool) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return err
+		}
+		*field(c) = b
+		return nil
+	}
+}
+
+func durationSetter(field func(*Config) *time.Duration) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		*field(c) = d
+		return nil
+	}
+}
+
+// setKeys parses "kid:secret" pairs separated by commas. They replace the
+// keys of the config file.
+func setKeys(c *Config, v string) error {
+	var keys []SigningKey
+	for _, pair := range strings.Split(v, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		kid, secret, ok := strings.Cut(pair, ":")
+		if !ok || kid == "" || secret == "" {
+			return fmt.Errorf("entry %q, expected kid:secret", pair)
+		}
+		keys = append(keys, SigningKey{ID: kid, Secret: secret})
+	}
+
+	c.Auth.Keys = keys
+	return nil
+}
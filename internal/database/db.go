@@ -1,35 +1,39 @@
 package database
 
 import (
+	"avito-internship/internal/config"
 	"database/sql"
-	"fmt"
 	"log"
-	"os"
 
 	_ "github.com/lib/pq"
 )
 
 var DB *sql.DB
 
-func Connect() {
-	host := os.Getenv("DB_HOST")
-	port := os.Getenv("DB_PORT")
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	dbname := os.Getenv("DB_NAME")
-
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		host, port, user, password, dbname)
-
+func Connect(cfg config.DatabaseConfig) {
 	var err error
-	DB, err = sql.Open("postgres", dsn)
+	DB, err = sql.Open("postgres", cfg.ConnString())
 	if err != nil {
 		log.Fatalf("Could not connect to database: %v", err)
 	}
 
+	DB.SetMaxOpenConns(cfg.MaxOpenConns)
+	DB.SetMaxIdleConns(cfg.MaxIdleConns)
+	DB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	DB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
 	if err = DB.Ping(); err != nil {
 		log.Fatalf("Could not ping database: %v", err)
 	}
 
 	log.Println("Successfully connected to database")
 }
+
+// Close closes the connection pool, if Connect opened one.
+func Close() error {
+	if DB == nil {
+		return nil
+	}
+
+	return DB.Close()
+}
@@ -1,22 +1,339 @@
 package database
 
 import (
-	"io/ioutil"
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
 	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
-func Migrate() {
-	filePath := "scripts/init.sql"
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockID is the key of the Postgres advisory lock held while
+// migrations run, so that several instances starting at once apply each
+// migration exactly once.
+const migrationLockID = 7252017041
+
+const createSchemaMigrations = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`
+
+// Migration is one schema change. Files are named NNNN_name.up.sql and
+// NNNN_name.down.sql; both halves are required.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// LoadMigrations reads migrations from the root of fsys, ordered by version.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		file := entry.Name()
+		if entry.IsDir() || path.Ext(file) != ".sql" {
+			continue
+		}
+
+		base := strings.TrimSuffix(file, ".sql")
+		direction := path.Ext(base)
+		if direction != ".up" && direction != ".down" {
+			return nil, fmt.Errorf("migration %s: expected .up.sql or .down.sql suffix", file)
+		}
+		base = strings.TrimSuffix(base, direction)
+
+		prefix, name, ok := strings.Cut(base, "_")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("migration %s: expected NNNN_name prefix", file)
+		}
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: invalid version %q", file, prefix)
+		}
+
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, m.Name, name)
+		}
+
+		if direction == ".up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s: both up and down files are required", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// NewMigrator returns a migrator over the migrations embedded in the binary.
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	dir, err := fs.Sub(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations, err := LoadMigrations(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Up applies every pending migration in version order and returns the ones it
+// applied. Each migration and its schema_migrations row commit together.
+func (m *Migrator) Up() ([]Migration, error) {
+	var applied []Migration
+
+	err := m.withLock(func(conn *sql.Conn) error {
+		done, err := appliedVersions(context.Background(), conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := done[migration.Version]; ok {
+				continue
+			}
+
+			if err := runMigration(conn, migration.Up,
+				`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`,
+				migration.Version, migration.Name); err != nil {
+				return fmt.Errorf("migration %04d_%s up: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down rolls back the most recently applied migration. It returns nil when
+// nothing is applied.
+func (m *Migrator) Down() (*Migration, error) {
+	var rolledBack *Migration
+
+	err := m.withLock(func(conn *sql.Conn) error {
+		var version int64
+		err := conn.QueryRowContext(context.Background(),
+			`SELECT version FROM schema_migrations ORDER BY version DESC LIMIT 1`).Scan(&version)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		migration, ok := m.find(version)
+		if !ok {
+			return fmt.Errorf("applied migration %d is unknown to this build", version)
+		}
+
+		if err := runMigration(conn, migration.Down,
+			`DELETE FROM schema_migrations WHERE version = $1`, migration.Version); err != nil {
+			return fmt.Errorf("migration %04d_%s down: %w", migration.Version, migration.Name, err)
+		}
+		rolledBack = &migration
+
+		return nil
+	})
+
+	return rolledBack, err
+}
+
+// Status reports every known migration with the time it was applied, or nil
+// if it is pending. It only reads: it neither waits for the migration lock nor
+// creates schema_migrations, whose absence means nothing is applied.
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	ctx := context.Background()
+
+	var exists bool
+	if err := m.db.QueryRowContext(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&exists); err != nil {
+		return nil, err
+	}
+
+	done := map[int64]time.Time{}
+	if exists {
+		var err error
+		if done, err = appliedVersions(ctx, m.db); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Migration: migration}
+		if at, ok := done[migration.Version]; ok {
+			status.AppliedAt = &at
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
 
-	content, err := ioutil.ReadFile(filePath)
+// CheckApplied returns an error unless every embedded migration is applied.
+// Like Status it does not wait for the migration lock, so that it can serve as
+// a readiness check while another instance migrates.
+func (m *Migrator) CheckApplied(ctx context.Context) error {
+	done, err := appliedVersions(ctx, m.db)
 	if err != nil {
-		log.Fatalf("failed to read %s: %v", filePath, err)
+		return err
+	}
+
+	var pending []string
+	for _, migration := range m.migrations {
+		if _, ok := done[migration.Version]; !ok {
+			pending = append(pending, fmt.Sprintf("%04d_%s", migration.Version, migration.Name))
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("pending migrations: %s", strings.Join(pending, ", "))
+	}
+
+	return nil
+}
+
+func (m *Migrator) find(version int64) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
 	}
+	return Migration{}, false
+}
 
-	_, err = DB.Exec(string(content))
+// withLock runs fn on a single connection holding the migration advisory lock.
+// The lock is session-scoped, so it has to be taken and released on the same
+// connection that does the work.
+func (m *Migrator) withLock(fn func(conn *sql.Conn) error) (err error) {
+	ctx := context.Background()
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err = conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer func() {
+		if _, unlockErr := conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, migrationLockID); unlockErr != nil && err == nil {
+			err = fmt.Errorf("release migration lock: %w", unlockErr)
+		}
+	}()
+
+	if _, err = conn.ExecContext(ctx, createSchemaMigrations); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func appliedVersions(ctx context.Context, conn queryer) (map[int64]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	done := map[int64]time.Time{}
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		done[version] = appliedAt
+	}
+
+	return done, rows.Err()
+}
+
+func runMigration(conn *sql.Conn, script, record string, args ...interface{}) error {
+	tx, err := conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(script); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec(record, args...); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Migrate brings the schema up to date on startup.
+func Migrate() {
+	migrator, err := NewMigrator(DB)
+	if err != nil {
+		log.Fatalf("failed to load migrations: %v", err)
+	}
+
+	applied, err := migrator.Up()
 	if err != nil {
 		log.Fatalf("migration execution error: %v", err)
 	}
 
+	for _, m := range applied {
+		log.Printf("Applied migration %04d_%s", m.Version, m.Name)
+	}
 	log.Println("Migration completed successfully")
 }
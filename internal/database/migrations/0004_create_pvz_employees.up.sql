@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS pvz_employees (
+    pvz_id UUID NOT NULL REFERENCES pvz(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    assigned_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (pvz_id, user_id)
+);
@@ -0,0 +1,7 @@
+ALTER TABLE products DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE products DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE products DROP COLUMN IF EXISTS created_by;
+
+ALTER TABLE receptions DROP COLUMN IF EXISTS closed_at;
+ALTER TABLE receptions DROP COLUMN IF EXISTS closed_by;
+ALTER TABLE receptions DROP COLUMN IF EXISTS created_by;
@@ -0,0 +1,10 @@
+-- Who performed each step. Deleted products are kept with deleted_by/deleted_at
+-- set so that the history of a reception can be reconstructed. Actors are not
+-- foreign keys so that the trail survives removal of the user.
+ALTER TABLE receptions ADD COLUMN IF NOT EXISTS created_by UUID;
+ALTER TABLE receptions ADD COLUMN IF NOT EXISTS closed_by UUID;
+ALTER TABLE receptions ADD COLUMN IF NOT EXISTS closed_at TIMESTAMPTZ;
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS created_by UUID;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS deleted_by UUID;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS receptions_one_open_per_pvz;
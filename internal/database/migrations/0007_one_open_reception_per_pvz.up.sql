@@ -0,0 +1,20 @@
+-- Concurrent CreateReception calls could already have left several open
+-- receptions for one PVZ, which the index below would refuse. Keep only the
+-- newest open; the others are closed without a closing actor.
+UPDATE receptions
+SET status = 'close', closed_at = now()
+WHERE id IN (
+    SELECT id FROM (
+        SELECT id, row_number() OVER (
+            PARTITION BY pvz_id ORDER BY date_time DESC NULLS LAST, id DESC
+        ) AS n
+        FROM receptions
+        WHERE status = 'in_progress'
+    ) open
+    WHERE n > 1
+);
+
+-- At most one open reception per PVZ, enforced by the database so that
+-- concurrent CreateReception calls cannot both succeed.
+CREATE UNIQUE INDEX IF NOT EXISTS receptions_one_open_per_pvz
+    ON receptions (pvz_id) WHERE status = 'in_progress';
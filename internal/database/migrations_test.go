@@ -0,0 +1,242 @@
+package database_test
+
+import (
+	"avito-internship/internal/database"
+	"context"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func file(content string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(content)}
+}
+
+func TestLoadMigrations_OrdersByVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_second.up.sql":   file("CREATE TABLE b ();"),
+		"0002_second.down.sql": file("DROP TABLE b;"),
+		"0001_first.up.sql":    file("CREATE TABLE a ();"),
+		"0001_first.down.sql":  file("DROP TABLE a;"),
+		"README.md":            file("not a migration"),
+	}
+
+	migrations, err := database.LoadMigrations(fsys)
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	require.Equal(t, int64(1), migrations[0].Version)
+	require.Equal(t, "first", migrations[0].Name)
+	require.Equal(t, "CREATE TABLE a ();", migrations[0].Up)
+	require.Equal(t, "DROP TABLE a;", migrations[0].Down)
+	require.Equal(t, int64(2), migrations[1].Version)
+}
+
+func TestLoadMigrations_Invalid(t *testing.T) {
+	cases := map[string]fstest.MapFS{
+		"missing down": {
+			"0001_first.up.sql": file("SELECT 1;"),
+		},
+		"bad version": {
+			"abc_first.up.sql":   file("SELECT 1;"),
+			"abc_first.down.sql": file("SELECT 1;"),
+		},
+		"no direction": {
+			"0001_first.sql": file("SELECT 1;"),
+		},
+		"conflicting names": {
+			"0001_first.up.sql":   file("SELECT 1;"),
+			"0001_other.down.sql": file("SELECT 1;"),
+		},
+	}
+
+	for name, fsys := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := database.LoadMigrations(fsys)
+			require.Error(t, err)
+		})
+	}
+}
+
+func expectLock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_lock($1)`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS schema_migrations`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_unlock($1)`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+// expectStatus expects the read-only queries of Status, without the lock.
+func expectStatus(mock sqlmock.Sqlmock, rows *sqlmock.Rows) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT to_regclass('schema_migrations') IS NOT NULL`)).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).WillReturnRows(rows)
+}
+
+func TestMigratorUp_SkipsApplied(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db)
+	require.NoError(t, err)
+
+	expectStatus(mock, sqlmock.NewRows([]string{"version", "applied_at"}))
+
+	statuses, err := migrator.Status()
+	require.NoError(t, err)
+	require.NotEmpty(t, statuses)
+	for i, s := range statuses {
+		require.Equal(t, int64(i+1), s.Version, "embedded migrations must be numbered without gaps")
+		require.Nil(t, s.AppliedAt)
+	}
+
+	// Everything but the last migration is already applied.
+	rows := sqlmock.NewRows([]string{"version", "applied_at"})
+	for _, s := range statuses[:len(statuses)-1] {
+		rows.AddRow(s.Version, time.Now())
+	}
+	last := statuses[len(statuses)-1]
+
+	expectLock(mock)
+	mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).WillReturnRows(rows)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(last.Up)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO schema_migrations`).
+		WithArgs(last.Version, last.Name).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectUnlock(mock)
+
+	applied, err := migrator.Up()
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	require.Equal(t, last.Version, applied[0].Version)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigratorUp_FailureRollsBack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db)
+	require.NoError(t, err)
+
+	expectLock(mock)
+	mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}))
+	mock.ExpectBegin()
+	mock.ExpectExec(`CREATE EXTENSION`).WillReturnError(sqlmock.ErrCancelled)
+	mock.ExpectRollback()
+	expectUnlock(mock)
+
+	applied, err := migrator.Up()
+	require.Error(t, err)
+	require.Empty(t, applied)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigratorDown_RollsBackLatest(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db)
+	require.NoError(t, err)
+
+	expectLock(mock)
+	mock.ExpectQuery(`SELECT version FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1))
+	mock.ExpectBegin()
+	mock.ExpectExec(`DROP TABLE IF EXISTS products`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM schema_migrations`).
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectUnlock(mock)
+
+	rolledBack, err := migrator.Down()
+	require.NoError(t, err)
+	require.NotNil(t, rolledBack)
+	require.Equal(t, int64(1), rolledBack.Version)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigratorDown_NothingApplied(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db)
+	require.NoError(t, err)
+
+	expectLock(mock)
+	mock.ExpectQuery(`SELECT version FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}))
+	expectUnlock(mock)
+
+	rolledBack, err := migrator.Down()
+	require.NoError(t, err)
+	require.Nil(t, rolledBack)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigratorStatus_NoSchemaTable(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db)
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT to_regclass('schema_migrations') IS NOT NULL`)).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	statuses, err := migrator.Status()
+	require.NoError(t, err)
+	require.NotEmpty(t, statuses)
+	for _, s := range statuses {
+		require.Nil(t, s.AppliedAt)
+	}
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigratorCheckApplied(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db)
+	require.NoError(t, err)
+
+	expectStatus(mock, sqlmock.NewRows([]string{"version", "applied_at"}))
+
+	statuses, err := migrator.Status()
+	require.NoError(t, err)
+
+	all := sqlmock.NewRows([]string{"version", "applied_at"})
+	for _, s := range statuses {
+		all.AddRow(s.Version, time.Now())
+	}
+	partial := sqlmock.NewRows([]string{"version", "applied_at"})
+	for _, s := range statuses[:len(statuses)-1] {
+		partial.AddRow(s.Version, time.Now())
+	}
+
+	// No lock is taken, so the check does not wait for a running migration.
+	mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).WillReturnRows(all)
+	require.NoError(t, migrator.CheckApplied(context.Background()))
+
+	mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).WillReturnRows(partial)
+	err = migrator.CheckApplied(context.Background())
+	require.ErrorContains(t, err, "pending migrations")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
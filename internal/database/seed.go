@@ -0,0 +1,24 @@
+package database
+
+import (
+	"avito-internship/internal/auth"
+	"context"
+	"database/sql"
+)
+
+// SeedDummyUsers creates the fixed identities behind /dummyLogin tokens, so
+// that dev accounts can be assigned to PVZs like real ones. The password hash
+// never matches. Existing rows are left alone.
+func SeedDummyUsers(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+        INSERT INTO users (id, email, password_hash, role) VALUES
+            ($1, 'dummy-employee@localhost', '!', $2),
+            ($3, 'dummy-moderator@localhost', '!', $4),
+            ($5, 'dummy-admin@localhost', '!', $6)
+        ON CONFLICT DO NOTHING
+    `, auth.DummyEmployeeID, auth.RoleEmployee,
+		auth.DummyModeratorID, auth.RoleModerator,
+		auth.DummyAdminID, auth.RoleAdmin)
+
+	return err
+}
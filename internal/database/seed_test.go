@@ -0,0 +1,24 @@
+package database_test
+
+import (
+	"avito-internship/internal/auth"
+	"avito-internship/internal/database"
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeedDummyUsers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(`INSERT INTO users .* ON CONFLICT DO NOTHING`).
+		WithArgs(auth.DummyEmployeeID, auth.RoleEmployee, auth.DummyModeratorID, auth.RoleModerator, auth.DummyAdminID, auth.RoleAdmin).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	require.NoError(t, database.SeedDummyUsers(context.Background(), db))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,45 @@
+// Package health tracks whether the instance should receive traffic.
+package health
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Check probes one dependency and returns an error when it is unusable.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Readiness combines the dependency checks with the drain state entered at
+// shutdown.
+type Readiness struct {
+	checks   []Check
+	draining atomic.Bool
+}
+
+func NewReadiness(checks ...Check) *Readiness {
+	return &Readiness{checks: checks}
+}
+
+// Drain makes the instance report itself as not ready from now on, so that
+// load balancers stop routing to it before its listeners close.
+func (r *Readiness) Drain() {
+	r.draining.Store(true)
+}
+
+func (r *Readiness) Draining() bool {
+	return r.draining.Load()
+}
+
+// Check runs every check and returns the failed ones by name.
+func (r *Readiness) Check(ctx context.Context) map[string]error {
+	failed := map[string]error{}
+	for _, c := range r.checks {
+		if err := c.Run(ctx); err != nil {
+			failed[c.Name] = err
+		}
+	}
+	return failed
+}
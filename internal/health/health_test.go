@@ -0,0 +1,29 @@
+package health_test
+
+import (
+	"avito-internship/internal/health"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadiness_Check(t *testing.T) {
+	down := errors.New("connection refused")
+	r := health.NewReadiness(
+		health.Check{Name: "database", Run: func(context.Context) error { return down }},
+		health.Check{Name: "cache", Run: func(context.Context) error { return nil }},
+	)
+
+	failed := r.Check(context.Background())
+	assert.Equal(t, map[string]error{"database": down}, failed)
+}
+
+func TestReadiness_Drain(t *testing.T) {
+	r := health.NewReadiness()
+	assert.False(t, r.Draining())
+
+	r.Drain()
+	assert.True(t, r.Draining())
+}
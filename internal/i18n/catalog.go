@@ -0,0 +1,112 @@
+package i18n
+
+// messages are keyed by error code for error responses; field error and
+// success message keys are prefixed with "field." and "ok." respectively.
+// Messages are sentences starting with a capital letter, field errors follow
+// the field name and stay lowercase.
+var messages = map[string]map[string]string{
+	English: {
+		"invalid_input":          "Invalid input",
+		"internal_error":         "Internal server error",
+		"timeout":                "The request took too long, try again later",
+		"request_canceled":       "The request was canceled",
+		"pvz_forbidden":          "Employee is not assigned to this PVZ",
+		"missing_token":          "Missing token",
+		"invalid_token":          "Invalid token",
+		"invalid_role":           "Invalid role",
+		"session_revoked":        "Session revoked",
+		"access_denied":          "Access denied",
+		"invalid_credentials":    "Invalid credentials",
+		"invalid_refresh_token":  "Invalid refresh token",
+		"role_not_allowed":       "Role not allowed",
+		"user_already_exists":    "User already exists",
+		"user_not_found":         "User not found",
+		"user_not_employee":      "User is not an employee",
+		"employee_not_assigned":  "Employee is not assigned to this PVZ",
+		"city_not_allowed":       "City not allowed",
+		"pvz_already_exists":     "PVZ already exists",
+		"pvz_not_found":          "PVZ not found",
+		"invalid_cursor":         "Invalid cursor",
+		"reception_already_open": "There is already an open reception",
+		"no_active_reception":    "No active reception",
+		"no_products_to_delete":  "No products to delete",
+
+		"field.integer":    "must be an integer",
+		"field.boolean":    "must be true or false",
+		"field.min":        "must be at least %d",
+		"field.max":        "must be at most %d",
+		"field.date":       "must be a date (YYYY-MM-DD) or date-time (RFC 3339, offset optional)",
+		"field.one_of":     "must be one of: %s",
+		"field.not_before": "must not be before %s",
+		"field.cursor":     "is not a cursor returned by this endpoint",
+
+		"ok.logged_out":          "Logged out",
+		"ok.employee_unassigned": "Employee unassigned",
+		"ok.reception_closed":    "Reception has been closed",
+		"ok.product_deleted":     "Last product deleted successfully",
+	},
+	Russian: {
+		"invalid_input":          "Некорректный запрос",
+		"internal_error":         "Внутренняя ошибка сервера",
+		"timeout":                "Запрос выполнялся слишком долго, повторите позже",
+		"request_canceled":       "Запрос отменён",
+		"pvz_forbidden":          "Сотрудник не закреплён за этим ПВЗ",
+		"missing_token":          "Отсутствует токен",
+		"invalid_token":          "Недействительный токен",
+		"invalid_role":           "Недопустимая роль",
+		"session_revoked":        "Сессия отозвана",
+		"access_denied":          "Доступ запрещён",
+		"invalid_credentials":    "Неверный email или пароль",
+		"invalid_refresh_token":  "Недействительный refresh-токен",
+		"role_not_allowed":       "Роль недоступна",
+		"user_already_exists":    "Пользователь уже существует",
+		"user_not_found":         "Пользователь не найден",
+		"user_not_employee":      "Пользователь не является сотрудником",
+		"employee_not_assigned":  "Сотрудник не закреплён за этим ПВЗ",
+		"city_not_allowed":       "ПВЗ нельзя открыть в этом городе",
+		"pvz_already_exists":     "ПВЗ уже существует",
+		"pvz_not_found":          "ПВЗ не найден",
+		"invalid_cursor":         "Некорректный курсор",
+		"reception_already_open": "В ПВЗ уже есть открытая приёмка",
+		"no_active_reception":    "Нет открытой приёмки",
+		"no_products_to_delete":  "Нет товаров для удаления",
+
+		"field.integer":    "должно быть целым числом",
+		"field.boolean":    "должно быть true или false",
+		"field.min":        "должно быть не меньше %d",
+		"field.max":        "должно быть не больше %d",
+		"field.date":       "должно быть датой (ГГГГ-ММ-ДД) или датой со временем (RFC 3339, смещение необязательно)",
+		"field.one_of":     "должно быть одним из: %s",
+		"field.not_before": "не должно быть раньше %s",
+		"field.cursor":     "не является курсором, выданным этим методом",
+
+		"ok.logged_out":          "Выход выполнен",
+		"ok.employee_unassigned": "Сотрудник откреплён",
+		"ok.reception_closed":    "Приёмка закрыта",
+		"ok.product_deleted":     "Последний товар удалён",
+	},
+}
+
+// names are the display names of domain values, which are stored in Russian.
+var names = map[string]map[string]string{
+	English: {
+		"Москва":          "Moscow",
+		"Санкт-Петербург": "Saint Petersburg",
+		"Казань":          "Kazan",
+		"электроника":     "Electronics",
+		"одежда":          "Clothing",
+		"обувь":           "Shoes",
+		"in_progress":     "In progress",
+		"close":           "Closed",
+	},
+	Russian: {
+		"Москва":          "Москва",
+		"Санкт-Петербург": "Санкт-Петербург",
+		"Казань":          "Казань",
+		"электроника":     "Электроника",
+		"одежда":          "Одежда",
+		"обувь":           "Обувь",
+		"in_progress":     "В процессе",
+		"close":           "Закрыта",
+	},
+}
@@ -0,0 +1,20 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// A key missing from one language would silently fall back to the default
+// one, so both catalogs must list the same keys.
+func TestCatalogsHaveSameKeys(t *testing.T) {
+	for _, catalog := range []map[string]map[string]string{messages, names} {
+		for key := range catalog[English] {
+			assert.Contains(t, catalog[Russian], key)
+		}
+		for key := range catalog[Russian] {
+			assert.Contains(t, catalog[English], key)
+		}
+	}
+}
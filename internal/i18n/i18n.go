@@ -0,0 +1,89 @@
+// Package i18n holds the Russian and English texts of API messages and of the
+// display names of domain values.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	English = "en"
+	Russian = "ru"
+)
+
+// DefaultLanguage is used when the client accepts none of the supported
+// languages, and for keys missing from the chosen catalog.
+var DefaultLanguage = English
+
+// Negotiate picks the supported language the Accept-Language header value
+// prefers most. Region subtags are ignored, so "en-GB" selects English.
+func Negotiate(acceptLanguage string) string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+
+		primary, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if primary == "*" {
+			primary = DefaultLanguage
+		}
+		if _, ok := messages[primary]; ok {
+			candidates = append(candidates, candidate{primary, q})
+		}
+	}
+	if len(candidates) == 0 {
+		return DefaultLanguage
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang
+}
+
+// Text returns the message for key in lang, formatted with args when given.
+// A key missing from lang falls back to DefaultLanguage, then to the key
+// itself.
+func Text(lang, key string, args ...interface{}) string {
+	text, ok := messages[lang][key]
+	if !ok {
+		text, ok = messages[DefaultLanguage][key]
+	}
+	if !ok {
+		text = key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+
+	return text
+}
+
+// Name returns the display name of a city, product type or reception status,
+// or the value itself when it has none.
+func Name(lang, value string) string {
+	if name, ok := names[lang][value]; ok {
+		return name
+	}
+	if name, ok := names[DefaultLanguage][value]; ok {
+		return name
+	}
+
+	return value
+}
@@ -0,0 +1,74 @@
+package i18n_test
+
+import (
+	"avito-internship/internal/i18n"
+	"avito-internship/internal/services"
+	"avito-internship/internal/transport/errcode"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	cases := map[string]string{
+		"":                        i18n.English,
+		"ru":                      i18n.Russian,
+		"ru-RU,ru;q=0.9,en;q=0.8": i18n.Russian,
+		"en-GB":                   i18n.English,
+		"de, ru;q=0.5":            i18n.Russian,
+		"en;q=0.3, ru;q=0.7":      i18n.Russian,
+		"ru;q=0, en":              i18n.English,
+		"fr, de":                  i18n.English,
+		"*":                       i18n.English,
+		"RU":                      i18n.Russian,
+		"ru;q=oops, en;q=0.1":     i18n.English,
+		"en;q=0.5, ru;q=0.5":      i18n.English,
+	}
+
+	for header, want := range cases {
+		assert.Equal(t, want, i18n.Negotiate(header), "Accept-Language: %q", header)
+	}
+}
+
+func TestText(t *testing.T) {
+	assert.Equal(t, "Invalid input", i18n.Text(i18n.English, "invalid_input"))
+	assert.Equal(t, "Некорректный запрос", i18n.Text(i18n.Russian, "invalid_input"))
+	assert.Equal(t, "должно быть не меньше 1", i18n.Text(i18n.Russian, "field.min", 1))
+	assert.Equal(t, "Invalid input", i18n.Text("de", "invalid_input"))
+	assert.Equal(t, "unknown_key", i18n.Text(i18n.Russian, "unknown_key"))
+}
+
+func TestName(t *testing.T) {
+	assert.Equal(t, "Moscow", i18n.Name(i18n.English, "Москва"))
+	assert.Equal(t, "Закрыта", i18n.Name(i18n.Russian, "close"))
+	assert.Equal(t, "Новосибирск", i18n.Name(i18n.English, "Новосибирск"))
+}
+
+func TestCatalogsCoverDomainErrors(t *testing.T) {
+	domainErrors := []*services.Error{
+		services.ErrInvalidCredentials, services.ErrInvalidRefreshToken, services.ErrRoleNotAllowed, services.ErrPasswordTooLong,
+		services.ErrUserExists, services.ErrUserNotFound, services.ErrNotEmployee, services.ErrNotAssigned,
+		services.ErrCityNotAllowed, services.ErrPVZExists, services.ErrPVZNotFound, services.ErrInvalidCursor,
+		services.ErrReceptionAlreadyOpen, services.ErrNoActiveReception, services.ErrNoProductsToDelete,
+	}
+
+	for _, err := range domainErrors {
+		for _, lang := range []string{i18n.English, i18n.Russian} {
+			assert.NotEqual(t, err.Code, i18n.Text(lang, err.Code), "%s has no %s text", err.Code, lang)
+		}
+		assert.Equal(t, err.Message, i18n.Text(i18n.English, err.Code))
+	}
+}
+
+func TestCatalogsCoverTransportErrors(t *testing.T) {
+	codes := []string{
+		errcode.InvalidInput, errcode.Internal, errcode.PVZForbidden, errcode.Timeout, errcode.Canceled,
+		errcode.MissingToken, errcode.InvalidToken, errcode.InvalidRole, errcode.SessionRevoked, errcode.AccessDenied,
+	}
+
+	for _, code := range codes {
+		for _, lang := range []string{i18n.English, i18n.Russian} {
+			assert.NotEqual(t, code, i18n.Text(lang, code), "%s has no %s text", code, lang)
+		}
+	}
+}
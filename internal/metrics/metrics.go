@@ -0,0 +1,63 @@
+// Package metrics defines the Prometheus metrics of the service. They are
+// registered with the default registry, which Handler serves.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "pvz"
+
+var (
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by route, method and status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP response time by route, method and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	PVZCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "pvz_created_total",
+		Help:      "PVZs created.",
+	})
+
+	ReceptionsCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "receptions_created_total",
+		Help:      "Receptions opened.",
+	})
+
+	ReceptionsClosed = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "receptions_closed_total",
+		Help:      "Receptions closed.",
+	})
+
+	ProductsAdded = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "products_added_total",
+		Help:      "Products added to receptions, by type.",
+	}, []string{"type"})
+
+	ProductsDeleted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "products_deleted_total",
+		Help:      "Products deleted from receptions, by type.",
+	}, []string{"type"})
+)
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditPVZCreate       = "pvz.create"
+	AuditReceptionCreate = "reception.create"
+	AuditReceptionClose  = "reception.close"
+	AuditProductAdd      = "product.add"
+	AuditProductDelete   = "product.delete"
+)
+
+type AuditEntry struct {
+	ID         int64     `json:"id"`
+	OccurredAt time.Time `json:"occurredAt"`
+	ActorID    *string   `json:"actorId"`
+	Action     string    `json:"action"`
+	EntityType string    `json:"entityType"`
+	EntityID   string    `json:"entityId"`
+	PVZID      *string   `json:"pvzId"`
+	// Before and After are snapshots of the entity, null when it did not
+	// exist before or after the change.
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// PVZSnapshot, ReceptionSnapshot and ProductSnapshot are the forms entities
+// are stored in as the before and after states of audit entries, with the
+// same camelCase keys as the rest of the API.
+type PVZSnapshot struct {
+	ID               string    `json:"id"`
+	RegistrationDate time.Time `json:"registrationDate"`
+	City             string    `json:"city"`
+}
+
+func NewPVZSnapshot(p PVZ) PVZSnapshot {
+	return PVZSnapshot{ID: p.ID, RegistrationDate: p.RegistrationDate, City: p.City}
+}
+
+type ReceptionSnapshot struct {
+	ID        string     `json:"id"`
+	DateTime  time.Time  `json:"dateTime"`
+	PVZID     string     `json:"pvzId"`
+	Status    string     `json:"status"`
+	CreatedBy *string    `json:"createdBy"`
+	ClosedBy  *string    `json:"closedBy"`
+	ClosedAt  *time.Time `json:"closedAt"`
+}
+
+func NewReceptionSnapshot(r Reception) ReceptionSnapshot {
+	return ReceptionSnapshot{
+		ID:        r.ID,
+		DateTime:  r.DateTime,
+		PVZID:     r.PVZID,
+		Status:    r.Status,
+		CreatedBy: r.CreatedBy,
+		ClosedBy:  r.ClosedBy,
+		ClosedAt:  r.ClosedAt,
+	}
+}
+
+type ProductSnapshot struct {
+	ID          string     `json:"id"`
+	DateTime    time.Time  `json:"dateTime"`
+	Type        string     `json:"type"`
+	ReceptionID string     `json:"receptionId"`
+	CreatedBy   *string    `json:"createdBy"`
+	DeletedBy   *string    `json:"deletedBy"`
+	DeletedAt   *time.Time `json:"deletedAt"`
+}
+
+func NewProductSnapshot(p Product) ProductSnapshot {
+	return ProductSnapshot{
+		ID:          p.ID,
+		DateTime:    p.DateTime,
+		Type:        p.Type,
+		ReceptionID: p.ReceptionID,
+		CreatedBy:   p.CreatedBy,
+		DeletedBy:   p.DeletedBy,
+		DeletedAt:   p.DeletedAt,
+	}
+}
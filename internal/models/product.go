@@ -2,9 +2,14 @@ package models
 
 import "time"
 
+var ProductTypes = []string{"электроника", "одежда", "обувь"}
+
 type Product struct {
 	ID          string
 	DateTime    time.Time
 	Type        string
 	ReceptionID string
+	CreatedBy   *string
+	DeletedBy   *string
+	DeletedAt   *time.Time
 }
@@ -2,8 +2,18 @@ package models
 
 import "time"
 
+// Cities are the cities a PVZ may be opened in.
+var Cities = []string{"Москва", "Санкт-Петербург", "Казань"}
+
 type PVZ struct {
 	ID               string
 	RegistrationDate time.Time
 	City             string
 }
+
+// PVZStats summarises the receptions of a PVZ. Deleted products are not
+// counted.
+type PVZStats struct {
+	ReceptionCount int
+	ProductCounts  map[string]int
+}
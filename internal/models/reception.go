@@ -2,9 +2,14 @@ package models
 
 import "time"
 
+var ReceptionStatuses = []string{"in_progress", "close"}
+
 type Reception struct {
-	ID       string
-	DateTime time.Time
-	Status   string
-	PVZID    string
+	ID        string
+	DateTime  time.Time
+	Status    string
+	PVZID     string
+	CreatedBy *string
+	ClosedBy  *string
+	ClosedAt  *time.Time
 }
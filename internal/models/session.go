@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+type Session struct {
+	ID        string
+	UserID    string
+	Role      string
+	ExpiresAt time.Time
+}
@@ -0,0 +1,8 @@
+package models
+
+type User struct {
+	ID           string `json:"id"`
+	Email        string `json:"email"`
+	Role         string `json:"role"`
+	PasswordHash string `json:"-"`
+}
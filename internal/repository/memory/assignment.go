@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"avito-internship/internal/models"
+	"avito-internship/internal/repository"
+	"context"
+)
+
+type AssignmentRepository struct {
+	store *Store
+}
+
+func NewAssignmentRepository(store *Store) *AssignmentRepository {
+	return &AssignmentRepository{store: store}
+}
+
+func (r *AssignmentRepository) Assign(_ context.Context, pvzID, userID string) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.pvz[pvzID]; !ok {
+		return repository.ErrPVZNotFound
+	}
+	if _, ok := s.users[userID]; !ok {
+		return repository.ErrUserNotFound
+	}
+
+	for _, a := range s.assignments[pvzID] {
+		if a.userID == userID {
+			return nil
+		}
+	}
+	s.assignments[pvzID] = append(s.assignments[pvzID], assignment{userID: userID, assignedAt: s.now()})
+
+	return nil
+}
+
+func (r *AssignmentRepository) Unassign(_ context.Context, pvzID, userID string) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	assigned := s.assignments[pvzID]
+	for i, a := range assigned {
+		if a.userID == userID {
+			s.assignments[pvzID] = append(assigned[:i:i], assigned[i+1:]...)
+			return nil
+		}
+	}
+
+	return repository.ErrNotAssigned
+}
+
+func (r *AssignmentRepository) ListEmployees(_ context.Context, pvzID string) ([]models.User, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users := []models.User{}
+	for _, a := range s.assignments[pvzID] {
+		if u, ok := s.users[a.userID]; ok {
+			users = append(users, u)
+		}
+	}
+
+	return users, nil
+}
+
+func (r *AssignmentRepository) IsAssigned(_ context.Context, userID, pvzID string) (bool, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.assignments[pvzID] {
+		if a.userID == userID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
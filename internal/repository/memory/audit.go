@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"avito-internship/internal/models"
+	"avito-internship/internal/repository"
+	"context"
+)
+
+type AuditRepository struct {
+	store *Store
+}
+
+func NewAuditRepository(store *Store) *AuditRepository {
+	return &AuditRepository{store: store}
+}
+
+func (r *AuditRepository) List(_ context.Context, filter repository.AuditFilter, limit, offset int) ([]models.AuditEntry, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := []models.AuditEntry{}
+	for i := len(s.audit) - 1; i >= 0 && len(entries) < limit; i-- {
+		e := s.audit[i]
+		if filter.PVZID != "" && (e.PVZID == nil || *e.PVZID != filter.PVZID) {
+			continue
+		}
+		if filter.ActorID != "" && (e.ActorID == nil || *e.ActorID != filter.ActorID) {
+			continue
+		}
+		if !inRange(e.OccurredAt, filter.From, filter.To) {
+			continue
+		}
+
+		if offset > 0 {
+			offset--
+			continue
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,312 @@
+package memory_test
+
+import (
+	"avito-internship/internal/auth"
+	"avito-internship/internal/models"
+	"avito-internship/internal/repository"
+	"avito-internship/internal/repository/memory"
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const pvzID = "11111111-1111-1111-1111-111111111111"
+
+var ctx = context.Background()
+
+func newStoreWithPVZ(t *testing.T) *memory.Store {
+	store := memory.NewStore()
+	_, err := memory.NewPVZRepository(store).Create(ctx, models.PVZ{ID: pvzID, City: "Москва", RegistrationDate: time.Now()}, "")
+	require.NoError(t, err)
+	return store
+}
+
+func TestReceptionCreate_OneOpenPerPVZ(t *testing.T) {
+	receptions := memory.NewReceptionRepository(newStoreWithPVZ(t))
+
+	_, err := receptions.Create(ctx, pvzID, "user-1")
+	require.NoError(t, err)
+
+	_, err = receptions.Create(ctx, pvzID, "user-1")
+	assert.ErrorIs(t, err, repository.ErrReceptionAlreadyOpen)
+
+	_, err = receptions.CloseLast(ctx, pvzID, "user-1")
+	require.NoError(t, err)
+
+	_, err = receptions.Create(ctx, pvzID, "user-1")
+	assert.NoError(t, err)
+}
+
+func TestReceptionCreate_Concurrent(t *testing.T) {
+	receptions := memory.NewReceptionRepository(newStoreWithPVZ(t))
+
+	const workers = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := receptions.Create(ctx, pvzID, "user-1")
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		if err == nil {
+			succeeded++
+		} else {
+			assert.ErrorIs(t, err, repository.ErrReceptionAlreadyOpen)
+		}
+	}
+	assert.Equal(t, 1, succeeded)
+}
+
+func TestReceptionCreate_UnknownPVZ(t *testing.T) {
+	_, err := memory.NewReceptionRepository(memory.NewStore()).Create(ctx, pvzID, "user-1")
+	assert.ErrorIs(t, err, repository.ErrPVZNotFound)
+}
+
+func TestProductDeleteLast_LIFO(t *testing.T) {
+	store := newStoreWithPVZ(t)
+	receptions := memory.NewReceptionRepository(store)
+	products := memory.NewProductRepository(store)
+
+	_, err := products.Add(ctx, pvzID, "обувь", "user-1")
+	assert.ErrorIs(t, err, repository.ErrNoOpenReception)
+
+	reception, err := receptions.Create(ctx, pvzID, "user-1")
+	require.NoError(t, err)
+
+	first, err := products.Add(ctx, pvzID, "обувь", "user-1")
+	require.NoError(t, err)
+	second, err := products.Add(ctx, pvzID, "одежда", "user-1")
+	require.NoError(t, err)
+
+	deleted, err := products.DeleteLast(ctx, pvzID, "user-2")
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, deleted.ID)
+	assert.Equal(t, "user-2", *deleted.DeletedBy)
+	assert.NotNil(t, deleted.DeletedAt)
+
+	// Deleted products are no longer listed.
+	list, err := products.ListByReceptions(ctx, []string{reception.ID}, "", false)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, first.ID, list[0].ID)
+
+	deleted, err = products.DeleteLast(ctx, pvzID, "user-2")
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, deleted.ID)
+
+	_, err = products.DeleteLast(ctx, pvzID, "user-2")
+	assert.ErrorIs(t, err, repository.ErrNoProducts)
+
+	list, err = products.ListByReceptions(ctx, []string{reception.ID}, "", false)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	// On request they are listed with who deleted them.
+	list, err = products.ListByReceptions(ctx, []string{reception.ID}, "", true)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "user-2", *list[0].DeletedBy)
+}
+
+func TestProductAdd_ClosedReception(t *testing.T) {
+	store := newStoreWithPVZ(t)
+	receptions := memory.NewReceptionRepository(store)
+	products := memory.NewProductRepository(store)
+
+	_, err := receptions.Create(ctx, pvzID, "user-1")
+	require.NoError(t, err)
+	_, err = products.Add(ctx, pvzID, "обувь", "user-1")
+	require.NoError(t, err)
+	_, err = receptions.CloseLast(ctx, pvzID, "user-1")
+	require.NoError(t, err)
+
+	_, err = products.Add(ctx, pvzID, "обувь", "user-1")
+	assert.ErrorIs(t, err, repository.ErrNoOpenReception)
+	_, err = products.DeleteLast(ctx, pvzID, "user-1")
+	assert.ErrorIs(t, err, repository.ErrNoOpenReception)
+}
+
+func TestAuditList_NewestFirst(t *testing.T) {
+	store := newStoreWithPVZ(t)
+	_, err := memory.NewReceptionRepository(store).Create(ctx, pvzID, "22222222-2222-2222-2222-222222222222")
+	require.NoError(t, err)
+
+	entries, err := memory.NewAuditRepository(store).List(ctx, repository.AuditFilter{PVZID: pvzID}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, models.AuditReceptionCreate, entries[0].Action)
+	assert.Equal(t, models.AuditPVZCreate, entries[1].Action)
+
+	entries, err = memory.NewAuditRepository(store).List(ctx, repository.AuditFilter{ActorID: "22222222-2222-2222-2222-222222222222"}, 10, 0)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestAuditSnapshots_CamelCase(t *testing.T) {
+	store := newStoreWithPVZ(t)
+	products := memory.NewProductRepository(store)
+	_, err := memory.NewReceptionRepository(store).Create(ctx, pvzID, "user-1")
+	require.NoError(t, err)
+	product, err := products.Add(ctx, pvzID, "обувь", "user-1")
+	require.NoError(t, err)
+	deleted, err := products.DeleteLast(ctx, pvzID, "user-2")
+	require.NoError(t, err)
+
+	entries, err := memory.NewAuditRepository(store).List(ctx, repository.AuditFilter{PVZID: pvzID}, 1, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, models.AuditProductDelete, entries[0].Action)
+
+	var after map[string]interface{}
+	require.NoError(t, json.Unmarshal(entries[0].After, &after))
+	assert.Equal(t, map[string]interface{}{
+		"id":          product.ID,
+		"dateTime":    product.DateTime.Format(time.RFC3339Nano),
+		"type":        "обувь",
+		"receptionId": product.ReceptionID,
+		"createdBy":   "user-1",
+		"deletedBy":   "user-2",
+		"deletedAt":   deleted.DeletedAt.Format(time.RFC3339Nano),
+	}, after)
+}
+
+func TestPVZListAfter_StableUnderInserts(t *testing.T) {
+	pvzs := memory.NewPVZRepository(memory.NewStore())
+
+	day := time.Date(2025, 4, 24, 0, 0, 0, 0, time.UTC)
+	ids := []string{
+		"11111111-1111-1111-1111-111111111111",
+		"22222222-2222-2222-2222-222222222222",
+		"33333333-3333-3333-3333-333333333333",
+	}
+	for _, id := range ids {
+		// Same registration date, so the order falls back to id.
+		_, err := pvzs.Create(ctx, models.PVZ{ID: id, City: "Москва", RegistrationDate: day}, "")
+		require.NoError(t, err)
+	}
+
+	page, err := pvzs.ListAfter(ctx, repository.PVZFilter{}, nil, 2)
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, ids[2], page[0].ID)
+	assert.Equal(t, ids[1], page[1].ID)
+
+	// A newer PVZ appearing between requests must not shift the next page.
+	_, err = pvzs.Create(ctx, models.PVZ{ID: "44444444-4444-4444-4444-444444444444", City: "Казань", RegistrationDate: day.Add(time.Hour)}, "")
+	require.NoError(t, err)
+
+	last := page[len(page)-1]
+	page, err = pvzs.ListAfter(ctx, repository.PVZFilter{}, &repository.PVZCursor{RegistrationDate: last.RegistrationDate, ID: last.ID}, 2)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, ids[0], page[0].ID)
+}
+
+func TestPVZStats_SkipsDeletedProducts(t *testing.T) {
+	store := newStoreWithPVZ(t)
+	receptions := memory.NewReceptionRepository(store)
+	products := memory.NewProductRepository(store)
+
+	_, err := receptions.Create(ctx, pvzID, "user-1")
+	require.NoError(t, err)
+	for _, typ := range []string{"обувь", "обувь", "одежда"} {
+		_, err = products.Add(ctx, pvzID, typ, "user-1")
+		require.NoError(t, err)
+	}
+	_, err = products.DeleteLast(ctx, pvzID, "user-1")
+	require.NoError(t, err)
+
+	stats, err := memory.NewPVZRepository(store).Stats(ctx, []string{pvzID, "unknown"}, repository.PVZFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats[pvzID].ReceptionCount)
+	assert.Equal(t, map[string]int{"обувь": 2}, stats[pvzID].ProductCounts)
+	assert.NotContains(t, stats, "unknown")
+
+	count, err := memory.NewPVZRepository(store).Count(ctx, repository.PVZFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestPVZList_Filters(t *testing.T) {
+	store := memory.NewStore()
+	pvzs := memory.NewPVZRepository(store)
+	receptions := memory.NewReceptionRepository(store)
+	products := memory.NewProductRepository(store)
+
+	const (
+		kazanOpen   = "11111111-1111-1111-1111-111111111111"
+		kazanClosed = "22222222-2222-2222-2222-222222222222"
+		moscow      = "33333333-3333-3333-3333-333333333333"
+	)
+	for id, city := range map[string]string{kazanOpen: "Казань", kazanClosed: "Казань", moscow: "Москва"} {
+		_, err := pvzs.Create(ctx, models.PVZ{ID: id, City: city, RegistrationDate: time.Now()}, "")
+		require.NoError(t, err)
+	}
+
+	_, err := receptions.Create(ctx, kazanOpen, "user-1")
+	require.NoError(t, err)
+	_, err = products.Add(ctx, kazanOpen, "одежда", "user-1")
+	require.NoError(t, err)
+
+	_, err = receptions.Create(ctx, kazanClosed, "user-1")
+	require.NoError(t, err)
+	_, err = products.Add(ctx, kazanClosed, "электроника", "user-1")
+	require.NoError(t, err)
+	_, err = products.Add(ctx, kazanClosed, "обувь", "user-1")
+	require.NoError(t, err)
+	_, err = receptions.CloseLast(ctx, kazanClosed, "user-1")
+	require.NoError(t, err)
+
+	ids := func(filter repository.PVZFilter) []string {
+		list, err := pvzs.List(ctx, filter, 10, 0)
+		require.NoError(t, err)
+		var out []string
+		for _, pvz := range list {
+			out = append(out, pvz.ID)
+		}
+		return out
+	}
+
+	assert.ElementsMatch(t, []string{kazanOpen, kazanClosed}, ids(repository.PVZFilter{Cities: []string{"Казань"}}))
+	assert.ElementsMatch(t, []string{kazanOpen, kazanClosed, moscow}, ids(repository.PVZFilter{Cities: []string{"Казань", "Москва"}}))
+	assert.Equal(t, []string{kazanOpen}, ids(repository.PVZFilter{Cities: []string{"Казань"}, ReceptionStatus: "in_progress"}))
+	assert.Equal(t, []string{kazanClosed}, ids(repository.PVZFilter{ProductType: "электроника"}))
+	assert.Empty(t, ids(repository.PVZFilter{ReceptionStatus: "in_progress", ProductType: "электроника"}))
+
+	// Nested lists honour the same filter.
+	recs, err := receptions.ListByPVZs(ctx, []string{kazanOpen, kazanClosed}, repository.PVZFilter{ProductType: "электроника"})
+	require.NoError(t, err)
+	require.Len(t, recs, 1)
+	assert.Equal(t, kazanClosed, recs[0].PVZID)
+
+	list, err := products.ListByReceptions(ctx, []string{recs[0].ID}, "электроника", false)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "электроника", list[0].Type)
+}
+
+func TestSeedDummyUsers(t *testing.T) {
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+
+	_, err := users.GetByID(ctx, auth.DummyAdminID)
+	assert.ErrorIs(t, err, repository.ErrUserNotFound, "dummy users are only created on request")
+
+	store.SeedDummyUsers()
+	admin, err := users.GetByID(ctx, auth.DummyAdminID)
+	require.NoError(t, err)
+	assert.Equal(t, auth.RoleAdmin, admin.Role)
+}
@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"avito-internship/internal/models"
+	"avito-internship/internal/repository"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type ProductRepository struct {
+	store *Store
+}
+
+func NewProductRepository(store *Store) *ProductRepository {
+	return &ProductRepository{store: store}
+}
+
+func (r *ProductRepository) Add(_ context.Context, pvzID, productType, actorID string) (*models.Product, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	open := s.openReception(pvzID)
+	if open == nil {
+		return nil, repository.ErrNoOpenReception
+	}
+
+	product := models.Product{
+		ID:          uuid.NewString(),
+		DateTime:    s.now(),
+		Type:        productType,
+		ReceptionID: open.ID,
+		CreatedBy:   &actorID,
+	}
+
+	if err := s.writeAudit(actorID, models.AuditProductAdd, "product", product.ID, pvzID, nil, models.NewProductSnapshot(product)); err != nil {
+		return nil, err
+	}
+	stored := product
+	s.products[open.ID] = append(s.products[open.ID], &stored)
+
+	return &product, nil
+}
+
+// DeleteLast marks the most recently added product of the open reception as
+// deleted, keeping it for history like the Postgres backend does.
+func (r *ProductRepository) DeleteLast(_ context.Context, pvzID, actorID string) (*models.Product, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	open := s.openReception(pvzID)
+	if open == nil {
+		return nil, repository.ErrNoOpenReception
+	}
+
+	products := s.products[open.ID]
+	for i := len(products) - 1; i >= 0; i-- {
+		if products[i].DeletedAt != nil {
+			continue
+		}
+
+		before := *products[i]
+		after := before
+		deletedAt := s.now()
+		after.DeletedBy = &actorID
+		after.DeletedAt = &deletedAt
+
+		if err := s.writeAudit(actorID, models.AuditProductDelete, "product", after.ID, pvzID,
+			models.NewProductSnapshot(before), models.NewProductSnapshot(after)); err != nil {
+			return nil, err
+		}
+		*products[i] = after
+
+		return &after, nil
+	}
+
+	return nil, repository.ErrNoProducts
+}
+
+func (r *ProductRepository) ListByReceptions(_ context.Context, receptionIDs []string, productType string, includeDeleted bool) ([]models.Product, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var products []models.Product
+	for _, receptionID := range receptionIDs {
+		for _, p := range s.products[receptionID] {
+			if (includeDeleted || p.DeletedAt == nil) && (productType == "" || p.Type == productType) {
+				products = append(products, *p)
+			}
+		}
+	}
+
+	return products, nil
+}
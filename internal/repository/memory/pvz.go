@@ -0,0 +1,192 @@
+package memory
+
+import (
+	"avito-internship/internal/models"
+	"avito-internship/internal/repository"
+	"context"
+	"sort"
+	"time"
+)
+
+type PVZRepository struct {
+	store *Store
+}
+
+func NewPVZRepository(store *Store) *PVZRepository {
+	return &PVZRepository{store: store}
+}
+
+func (r *PVZRepository) Create(_ context.Context, pvz models.PVZ, actorID string) (*models.PVZ, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.pvz[pvz.ID]; ok {
+		return nil, repository.ErrPVZExists
+	}
+
+	if err := s.writeAudit(actorID, models.AuditPVZCreate, "pvz", pvz.ID, pvz.ID, nil, models.NewPVZSnapshot(pvz)); err != nil {
+		return nil, err
+	}
+	s.pvz[pvz.ID] = pvz
+
+	return &pvz, nil
+}
+
+func (r *PVZRepository) List(_ context.Context, filter repository.PVZFilter, limit, offset int) ([]models.PVZ, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var list []models.PVZ
+	for _, pvz := range s.pvz {
+		if s.pvzMatches(pvz, filter) {
+			list = append(list, pvz)
+		}
+	}
+
+	sortPVZs(list)
+
+	if offset >= len(list) {
+		return nil, nil
+	}
+	list = list[offset:]
+	if limit < len(list) {
+		list = list[:limit]
+	}
+
+	return list, nil
+}
+
+func (r *PVZRepository) ListAfter(_ context.Context, filter repository.PVZFilter, after *repository.PVZCursor, limit int) ([]models.PVZ, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var list []models.PVZ
+	for _, pvz := range s.pvz {
+		if !s.pvzMatches(pvz, filter) {
+			continue
+		}
+		if after != nil && !listedAfter(after.RegistrationDate, after.ID, pvz) {
+			continue
+		}
+		list = append(list, pvz)
+	}
+
+	sortPVZs(list)
+	if limit < len(list) {
+		list = list[:limit]
+	}
+
+	return list, nil
+}
+
+// sortPVZs orders PVZs newest first, breaking ties by id, to match the
+// postgres implementation.
+func sortPVZs(list []models.PVZ) {
+	sort.Slice(list, func(i, j int) bool {
+		return listedAfter(list[i].RegistrationDate, list[i].ID, list[j])
+	})
+}
+
+// listedAfter reports whether pvz comes after the (date, id) position in list
+// order.
+func listedAfter(date time.Time, id string, pvz models.PVZ) bool {
+	if !pvz.RegistrationDate.Equal(date) {
+		return pvz.RegistrationDate.Before(date)
+	}
+	return pvz.ID < id
+}
+
+func (r *PVZRepository) Count(_ context.Context, filter repository.PVZFilter) (int, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for _, pvz := range s.pvz {
+		if s.pvzMatches(pvz, filter) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *PVZRepository) Stats(_ context.Context, pvzIDs []string, filter repository.PVZFilter) (map[string]models.PVZStats, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := map[string]models.PVZStats{}
+	for _, pvzID := range pvzIDs {
+		st := models.PVZStats{ProductCounts: map[string]int{}}
+		for _, rec := range s.receptions[pvzID] {
+			if !s.receptionMatches(rec, filter) {
+				continue
+			}
+
+			st.ReceptionCount++
+			for _, p := range s.products[rec.ID] {
+				if p.DeletedAt == nil && (filter.ProductType == "" || p.Type == filter.ProductType) {
+					st.ProductCounts[p.Type]++
+				}
+			}
+		}
+
+		if st.ReceptionCount > 0 {
+			stats[pvzID] = st
+		}
+	}
+
+	return stats, nil
+}
+
+// pvzMatches mirrors the pvzFilterSQL clause of the postgres implementation.
+func (s *Store) pvzMatches(pvz models.PVZ, f repository.PVZFilter) bool {
+	if !inRange(pvz.RegistrationDate, f.StartDate, f.EndDate) {
+		return false
+	}
+	if len(f.Cities) > 0 && !contains(f.Cities, pvz.City) {
+		return false
+	}
+	if f.ReceptionStatus == "" && f.ProductType == "" {
+		return true
+	}
+
+	for _, rec := range s.receptions[pvz.ID] {
+		if s.receptionMatches(rec, f) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) receptionMatches(rec *models.Reception, f repository.PVZFilter) bool {
+	if !inRange(rec.DateTime, f.StartDate, f.EndDate) {
+		return false
+	}
+	if f.ReceptionStatus != "" && rec.Status != f.ReceptionStatus {
+		return false
+	}
+	if f.ProductType == "" {
+		return true
+	}
+
+	for _, p := range s.products[rec.ID] {
+		if p.DeletedAt == nil && p.Type == f.ProductType {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
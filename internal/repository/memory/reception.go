@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"avito-internship/internal/models"
+	"avito-internship/internal/repository"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type ReceptionRepository struct {
+	store *Store
+}
+
+func NewReceptionRepository(store *Store) *ReceptionRepository {
+	return &ReceptionRepository{store: store}
+}
+
+func (r *ReceptionRepository) Create(_ context.Context, pvzID, actorID string) (*models.Reception, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.pvz[pvzID]; !ok {
+		return nil, repository.ErrPVZNotFound
+	}
+	if s.openReception(pvzID) != nil {
+		return nil, repository.ErrReceptionAlreadyOpen
+	}
+
+	reception := models.Reception{
+		ID:        uuid.NewString(),
+		DateTime:  s.now(),
+		Status:    "in_progress",
+		PVZID:     pvzID,
+		CreatedBy: &actorID,
+	}
+
+	if err := s.writeAudit(actorID, models.AuditReceptionCreate, "reception", reception.ID, pvzID, nil, models.NewReceptionSnapshot(reception)); err != nil {
+		return nil, err
+	}
+	stored := reception
+	s.receptions[pvzID] = append(s.receptions[pvzID], &stored)
+
+	return &reception, nil
+}
+
+func (r *ReceptionRepository) CloseLast(_ context.Context, pvzID, actorID string) (*models.Reception, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	open := s.openReception(pvzID)
+	if open == nil {
+		return nil, repository.ErrNoOpenReception
+	}
+
+	before := *open
+	after := before
+	closedAt := s.now()
+	after.Status = "close"
+	after.ClosedBy = &actorID
+	after.ClosedAt = &closedAt
+
+	if err := s.writeAudit(actorID, models.AuditReceptionClose, "reception", after.ID, pvzID,
+		models.NewReceptionSnapshot(before), models.NewReceptionSnapshot(after)); err != nil {
+		return nil, err
+	}
+	*open = after
+
+	return &after, nil
+}
+
+func (r *ReceptionRepository) ListByPVZs(_ context.Context, pvzIDs []string, filter repository.PVZFilter) ([]models.Reception, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var receptions []models.Reception
+	for _, pvzID := range pvzIDs {
+		for _, rec := range s.receptions[pvzID] {
+			if s.receptionMatches(rec, filter) {
+				receptions = append(receptions, *rec)
+			}
+		}
+	}
+
+	return receptions, nil
+}
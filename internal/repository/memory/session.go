@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"avito-internship/internal/models"
+	"avito-internship/internal/repository"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SessionRepository struct {
+	store *Store
+}
+
+func NewSessionRepository(store *Store) *SessionRepository {
+	return &SessionRepository{store: store}
+}
+
+func (r *SessionRepository) Create(_ context.Context, sess models.Session, refreshTokenHash string) (*models.Session, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess.ID = uuid.NewString()
+	s.sessions[sess.ID] = &session{Session: sess, refreshTokenHash: refreshTokenHash}
+
+	return &sess, nil
+}
+
+func (r *SessionRepository) Rotate(_ context.Context, refreshTokenHash, newHash string, expiresAt time.Time) (*models.Session, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, sess := range s.sessions {
+		if sess.refreshTokenHash != refreshTokenHash || !s.active(sess) {
+			continue
+		}
+
+		user, ok := s.users[sess.UserID]
+		if !ok {
+			break
+		}
+
+		sess.refreshTokenHash = newHash
+		sess.ExpiresAt = expiresAt
+		sess.Role = user.Role
+
+		rotated := sess.Session
+		return &rotated, nil
+	}
+
+	return nil, repository.ErrSessionNotFound
+}
+
+func (r *SessionRepository) Revoke(_ context.Context, sessionID string) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sess, ok := s.sessions[sessionID]; ok {
+		sess.revoked = true
+	}
+
+	return nil
+}
+
+func (r *SessionRepository) RevokeByUser(_ context.Context, userID string) (int64, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var revoked int64
+	for _, sess := range s.sessions {
+		if sess.UserID == userID && !sess.revoked {
+			sess.revoked = true
+			revoked++
+		}
+	}
+
+	return revoked, nil
+}
+
+func (r *SessionRepository) IsActive(_ context.Context, sessionID string) (bool, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[sessionID]
+	return ok && s.active(sess), nil
+}
+
+// active must be called with s.mu held.
+func (s *Store) active(sess *session) bool {
+	return !sess.revoked && sess.ExpiresAt.After(s.now())
+}
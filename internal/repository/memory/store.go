@@ -0,0 +1,122 @@
+package memory
+
+import (
+	"avito-internship/internal/auth"
+	"avito-internship/internal/models"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+type session struct {
+	models.Session
+	refreshTokenHash string
+	revoked          bool
+}
+
+type assignment struct {
+	userID     string
+	assignedAt time.Time
+}
+
+// Store keeps all data in process memory. One mutex serialises every access,
+// which gives the repositories the guarantees the Postgres backend gets from
+// its constraints and row locks.
+type Store struct {
+	mu  sync.Mutex
+	now func() time.Time
+
+	users       map[string]models.User
+	sessions    map[string]*session
+	pvz         map[string]models.PVZ
+	receptions  map[string][]*models.Reception // by PVZ, oldest first
+	products    map[string][]*models.Product   // by reception, oldest first
+	assignments map[string][]assignment        // by PVZ, oldest first
+	audit       []models.AuditEntry
+}
+
+func NewStore() *Store {
+	s := &Store{
+		now:         time.Now,
+		users:       make(map[string]models.User),
+		sessions:    make(map[string]*session),
+		pvz:         make(map[string]models.PVZ),
+		receptions:  make(map[string][]*models.Reception),
+		products:    make(map[string][]*models.Product),
+		assignments: make(map[string][]assignment),
+	}
+
+	return s
+}
+
+// SeedDummyUsers creates the same fixed identities as the Postgres seed, so
+// /dummyLogin users can be assigned to PVZs.
+func (s *Store) SeedDummyUsers() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, role := range map[string]string{
+		auth.DummyEmployeeID:  auth.RoleEmployee,
+		auth.DummyModeratorID: auth.RoleModerator,
+		auth.DummyAdminID:     auth.RoleAdmin,
+	} {
+		if _, ok := s.users[id]; !ok {
+			s.users[id] = models.User{ID: id, Email: "dummy-" + role + "@localhost", Role: role, PasswordHash: "!"}
+		}
+	}
+}
+
+// openReception returns the reception in progress for the PVZ, if any. The
+// caller must hold s.mu.
+func (s *Store) openReception(pvzID string) *models.Reception {
+	receptions := s.receptions[pvzID]
+	for i := len(receptions) - 1; i >= 0; i-- {
+		if receptions[i].Status == "in_progress" {
+			return receptions[i]
+		}
+	}
+	return nil
+}
+
+// writeAudit appends an audit entry. The caller must hold s.mu, so the entry
+// is recorded together with the change it describes.
+func (s *Store) writeAudit(actorID, action, entityType, entityID, pvzID string, before, after interface{}) error {
+	entry := models.AuditEntry{
+		ID:         int64(len(s.audit) + 1),
+		OccurredAt: s.now(),
+		ActorID:    optional(actorID),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		PVZID:      optional(pvzID),
+	}
+
+	var err error
+	if entry.Before, err = marshalAuditState(before); err != nil {
+		return err
+	}
+	if entry.After, err = marshalAuditState(after); err != nil {
+		return err
+	}
+
+	s.audit = append(s.audit, entry)
+	return nil
+}
+
+func marshalAuditState(v interface{}) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func inRange(t time.Time, from, to *time.Time) bool {
+	return (from == nil || !t.Before(*from)) && (to == nil || !t.After(*to))
+}
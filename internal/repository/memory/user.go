@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"avito-internship/internal/models"
+	"avito-internship/internal/repository"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type UserRepository struct {
+	store *Store
+}
+
+func NewUserRepository(store *Store) *UserRepository {
+	return &UserRepository{store: store}
+}
+
+func (r *UserRepository) Create(_ context.Context, user models.User) (*models.User, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, u := range s.users {
+		if u.Email == user.Email {
+			return nil, repository.ErrUserExists
+		}
+	}
+
+	user.ID = uuid.NewString()
+	s.users[user.ID] = user
+
+	return &user, nil
+}
+
+func (r *UserRepository) GetByEmail(_ context.Context, email string) (*models.User, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, u := range s.users {
+		if u.Email == email {
+			return &u, nil
+		}
+	}
+
+	return nil, repository.ErrUserNotFound
+}
+
+func (r *UserRepository) GetByID(_ context.Context, id string) (*models.User, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[id]
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+
+	return &u, nil
+}
@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"avito-internship/internal/models"
+	"avito-internship/internal/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const foreignKeyViolation = "23503"
+
+type AssignmentRepository struct {
+	store
+}
+
+func NewAssignmentRepository(db *sql.DB, timeout time.Duration) *AssignmentRepository {
+	return &AssignmentRepository{store{db: db, timeout: timeout}}
+}
+
+func (r *AssignmentRepository) Assign(ctx context.Context, pvzID, userID string) (err error) {
+	ctx, done := r.query(ctx)
+	defer done(&err)
+
+	_, err = r.db.ExecContext(ctx, `
+        INSERT INTO pvz_employees (pvz_id, user_id)
+        VALUES ($1, $2)
+        ON CONFLICT DO NOTHING
+    `, pvzID, userID)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		return repository.ErrPVZNotFound
+	}
+	return err
+}
+
+func (r *AssignmentRepository) Unassign(ctx context.Context, pvzID, userID string) (err error) {
+	ctx, done := r.query(ctx)
+	defer done(&err)
+
+	res, err := r.db.ExecContext(ctx, `
+        DELETE FROM pvz_employees
+        WHERE pvz_id = $1 AND user_id = $2
+    `, pvzID, userID)
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return repository.ErrNotAssigned
+	}
+	return nil
+}
+
+func (r *AssignmentRepository) ListEmployees(ctx context.Context, pvzID string) (_ []models.User, err error) {
+	ctx, done := r.query(ctx)
+	defer done(&err)
+
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT u.id, u.email, u.role
+        FROM pvz_employees e
+        JOIN users u ON u.id = e.user_id
+        WHERE e.pvz_id = $1
+        ORDER BY e.assigned_at
+    `, pvzID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		var u models.User
+		if err := rows.Scan(&u.ID, &u.Email, &u.Role); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+
+	return users, rows.Err()
+}
+
+func (r *AssignmentRepository) IsAssigned(ctx context.Context, userID, pvzID string) (_ bool, err error) {
+	ctx, done := r.query(ctx)
+	defer done(&err)
+
+	var assigned bool
+	err = r.db.QueryRowContext(ctx, `
+        SELECT EXISTS (
+            SELECT 1 FROM pvz_employees
+            WHERE user_id = $1 AND pvz_id = $2
+        )
+    `, userID, pvzID).Scan(&assigned)
+	return assigned, err
+}
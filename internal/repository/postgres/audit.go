@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"avito-internship/internal/models"
+	"avito-internship/internal/repository"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+type AuditRepository struct {
+	store
+}
+
+func NewAuditRepository(db *sql.DB, timeout time.Duration) *AuditRepository {
+	return &AuditRepository{store{db: db, timeout: timeout}}
+}
+
+func (r *AuditRepository) List(ctx context.Context, filter repository.AuditFilter, limit, offset int) (_ []models.AuditEntry, err error) {
+	ctx, done := r.query(ctx)
+	defer done(&err)
+
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT id, occurred_at, actor_id, action, entity_type, entity_id, pvz_id, before, after
+        FROM audit_log
+        WHERE ($1::uuid IS NULL OR pvz_id = $1::uuid)
+          AND ($2::uuid IS NULL OR actor_id = $2::uuid)
+          AND ($3::timestamptz IS NULL OR occurred_at >= $3::timestamptz)
+          AND ($4::timestamptz IS NULL OR occurred_at <= $4::timestamptz)
+        ORDER BY occurred_at DESC, id DESC
+        LIMIT $5 OFFSET $6
+    `, nullIfEmpty(filter.PVZID), nullIfEmpty(filter.ActorID), filter.From, filter.To, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.AuditEntry{}
+	for rows.Next() {
+		var e models.AuditEntry
+		var before, after []byte
+		if err := rows.Scan(&e.ID, &e.OccurredAt, &e.ActorID, &e.Action, &e.EntityType, &e.EntityID, &e.PVZID, &before, &after); err != nil {
+			return nil, err
+		}
+		e.Before = before
+		e.After = after
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// writeAudit appends an entry to audit_log within the caller's transaction, so
+// the entry exists if and only if the mutation it describes was committed.
+// A nil before or after is stored as NULL.
+func writeAudit(ctx context.Context, tx *sql.Tx, actorID, action, entityType, entityID, pvzID string, before, after interface{}) error {
+	beforeJSON, err := marshalAuditState(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := marshalAuditState(after)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO audit_log (actor_id, action, entity_type, entity_id, pvz_id, before, after)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+    `, nullIfEmpty(actorID), action, entityType, entityID, nullIfEmpty(pvzID), beforeJSON, afterJSON)
+	return err
+}
+
+func marshalAuditState(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,53 @@
+package postgres_test
+
+import (
+	"avito-internship/internal/database"
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+var ctx = context.Background()
+
+// queryTimeout is generous enough for any test query to finish.
+const queryTimeout = time.Second
+
+// openTestDB connects to the Postgres instance named by TEST_DATABASE_DSN and
+// applies the schema. Tests that need real locking or constraint behaviour use
+// it and are skipped when no database is configured.
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := database.NewMigrator(db)
+	require.NoError(t, err)
+	_, err = migrator.Up()
+	require.NoError(t, err)
+
+	return db
+}
+
+func createTestPVZ(t *testing.T, db *sql.DB) string {
+	t.Helper()
+
+	var id string
+	require.NoError(t, db.QueryRow(`INSERT INTO pvz (city) VALUES ('Казань') RETURNING id`).Scan(&id))
+	t.Cleanup(func() {
+		_, _ = db.Exec(`DELETE FROM pvz WHERE id = $1`, id)
+	})
+
+	return id
+}
@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"avito-internship/internal/models"
+	"avito-internship/internal/repository"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type ProductRepository struct {
+	store
+}
+
+func NewProductRepository(db *sql.DB, timeout time.Duration) *ProductRepository {
+	return &ProductRepository{store{db: db, timeout: timeout}}
+}
+
+// Add keeps the open reception locked until the product and its audit entry
+// are written.
+func (r *ProductRepository) Add(ctx context.Context, pvzID, productType, actorID string) (*models.Product, error) {
+	var product models.Product
+	err := r.withTx(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		receptionID, err := lockOpenReception(ctx, tx, pvzID)
+		if err != nil {
+			return err
+		}
+
+		row := tx.QueryRowContext(ctx, `
+            INSERT INTO products (type, reception_id, created_by)
+            VALUES ($1, $2, $3)
+            RETURNING id, date_time
+        `, productType, receptionID, actorID)
+
+		if err := row.Scan(&product.ID, &product.DateTime); err != nil {
+			return err
+		}
+
+		product.Type = productType
+		product.ReceptionID = receptionID
+		product.CreatedBy = &actorID
+
+		return writeAudit(ctx, tx, actorID, models.AuditProductAdd, "product", product.ID, pvzID, nil, models.NewProductSnapshot(product))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &product, nil
+}
+
+// DeleteLast marks the most recently added product of the open reception as
+// deleted. The row is kept so the deletion stays traceable.
+func (r *ProductRepository) DeleteLast(ctx context.Context, pvzID, actorID string) (*models.Product, error) {
+	var after models.Product
+	err := r.withTx(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		receptionID, err := lockOpenReception(ctx, tx, pvzID)
+		if err != nil {
+			return err
+		}
+
+		var before models.Product
+		err = tx.QueryRowContext(ctx, `
+            SELECT id, date_time, type, reception_id, created_by
+            FROM products
+            WHERE reception_id = $1 AND deleted_at IS NULL
+            ORDER BY date_time DESC
+            LIMIT 1
+        `, receptionID).Scan(&before.ID, &before.DateTime, &before.Type, &before.ReceptionID, &before.CreatedBy)
+		if err == sql.ErrNoRows {
+			return repository.ErrNoProducts
+		} else if err != nil {
+			return err
+		}
+
+		after = before
+		after.DeletedBy = &actorID
+		err = tx.QueryRowContext(ctx, `
+            UPDATE products
+            SET deleted_by = $2, deleted_at = now()
+            WHERE id = $1
+            RETURNING deleted_at
+        `, before.ID, actorID).Scan(&after.DeletedAt)
+		if err != nil {
+			return err
+		}
+
+		return writeAudit(ctx, tx, actorID, models.AuditProductDelete, "product", before.ID, pvzID,
+			models.NewProductSnapshot(before), models.NewProductSnapshot(after))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &after, nil
+}
+
+func (r *ProductRepository) ListByReceptions(ctx context.Context, receptionIDs []string, productType string, includeDeleted bool) (_ []models.Product, err error) {
+	ctx, done := r.query(ctx)
+	defer done(&err)
+
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT id, reception_id, date_time, type, created_by, deleted_by, deleted_at
+        FROM products
+        WHERE reception_id = ANY($1) AND ($3 OR deleted_at IS NULL)
+          AND ($2::text = '' OR type = $2::text)
+        ORDER BY date_time
+    `, pq.Array(receptionIDs), productType, includeDeleted)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []models.Product
+	for rows.Next() {
+		var p models.Product
+		if err := rows.Scan(&p.ID, &p.ReceptionID, &p.DateTime, &p.Type, &p.CreatedBy, &p.DeletedBy, &p.DeletedAt); err != nil {
+			return nil, err
+		}
+		products = append(products, p)
+	}
+
+	return products, rows.Err()
+}
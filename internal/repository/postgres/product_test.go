@@ -0,0 +1,192 @@
+package postgres_test
+
+import (
+	"avito-internship/internal/auth"
+	"avito-internship/internal/models"
+	"avito-internship/internal/repository"
+	"avito-internship/internal/repository/postgres"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductAdd_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	db := sqlx.NewDb(sqlDB, "postgres")
+	pvzID := "pvz-1"
+	receptionID := "rec-1"
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`(?i)SELECT id FROM receptions.*status = 'in_progress'.*FOR UPDATE`).
+		WithArgs(pvzID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(receptionID))
+
+	mock.ExpectQuery(`(?i)INSERT INTO products`).
+		WithArgs("обувь", receptionID, "user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time"}).AddRow("prod-1", now))
+	mock.ExpectExec(`(?i)INSERT INTO audit_log`).
+		WithArgs("user-1", models.AuditProductAdd, "product", "prod-1", pvzID, nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	product, err := postgres.NewProductRepository(db.DB, queryTimeout).Add(ctx, pvzID, "обувь", "user-1")
+	require.NoError(t, err)
+	require.NotNil(t, product)
+	require.Equal(t, "обувь", product.Type)
+	require.Equal(t, receptionID, product.ReceptionID)
+	require.Equal(t, "prod-1", product.ID)
+	require.Equal(t, "user-1", *product.CreatedBy)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductAdd_NoReception(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	db := sqlx.NewDb(sqlDB, "postgres")
+	pvzID := "pvz-1"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`(?i)SELECT id FROM receptions.*status = 'in_progress'.*FOR UPDATE`).
+		WithArgs(pvzID).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	product, err := postgres.NewProductRepository(db.DB, queryTimeout).Add(ctx, pvzID, "обувь", "user-1")
+	require.Error(t, err)
+	require.Nil(t, product)
+	require.ErrorIs(t, err, repository.ErrNoOpenReception)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductDeleteLast_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	db := sqlx.NewDb(sqlDB, "postgres")
+	pvzID := "pvz-1"
+	productID := "prod-1"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`(?i)SELECT id FROM receptions.*status = 'in_progress'.*FOR UPDATE`).
+		WithArgs(pvzID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("rec-1"))
+	mock.ExpectQuery(`(?i)SELECT id, date_time, type, reception_id, created_by\s+FROM products`).
+		WithArgs("rec-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id", "created_by"}).
+			AddRow(productID, time.Now(), "обувь", "rec-1", "user-2"))
+
+	mock.ExpectQuery(`(?i)UPDATE products\s+SET deleted_by = \$2, deleted_at = now\(\)`).
+		WithArgs(productID, "user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"deleted_at"}).AddRow(time.Now()))
+	mock.ExpectExec(`(?i)INSERT INTO audit_log`).
+		WithArgs("user-1", models.AuditProductDelete, "product", productID, pvzID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	_, err = postgres.NewProductRepository(db.DB, queryTimeout).DeleteLast(ctx, pvzID, "user-1")
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductDeleteLast_NoProduct(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	db := sqlx.NewDb(sqlDB, "postgres")
+	pvzID := "pvz-1"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`(?i)SELECT id FROM receptions.*status = 'in_progress'.*FOR UPDATE`).
+		WithArgs(pvzID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("rec-1"))
+	mock.ExpectQuery(`(?i)SELECT id, date_time, type, reception_id, created_by\s+FROM products`).
+		WithArgs("rec-1").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	_, err = postgres.NewProductRepository(db.DB, queryTimeout).DeleteLast(ctx, pvzID, "user-1")
+	require.Error(t, err)
+	require.ErrorIs(t, err, repository.ErrNoProducts)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductListByReceptions_SkipsDeleted(t *testing.T) {
+	db := openTestDB(t)
+	pvzID := createTestPVZ(t, db)
+	products := postgres.NewProductRepository(db, queryTimeout)
+
+	reception, err := postgres.NewReceptionRepository(db, queryTimeout).Create(ctx, pvzID, auth.DummyEmployeeID)
+	require.NoError(t, err)
+	kept, err := products.Add(ctx, pvzID, "обувь", auth.DummyEmployeeID)
+	require.NoError(t, err)
+	_, err = products.Add(ctx, pvzID, "одежда", auth.DummyEmployeeID)
+	require.NoError(t, err)
+	_, err = products.DeleteLast(ctx, pvzID, auth.DummyEmployeeID)
+	require.NoError(t, err)
+
+	list, err := products.ListByReceptions(ctx, []string{reception.ID}, "", false)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, kept.ID, list[0].ID)
+
+	list, err = products.ListByReceptions(ctx, []string{reception.ID}, "", true)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	require.Nil(t, list[0].DeletedBy)
+	require.Equal(t, auth.DummyEmployeeID, *list[1].DeletedBy)
+	require.NotNil(t, list[1].DeletedAt)
+}
+
+func TestProductAdd_ConcurrentWithClose(t *testing.T) {
+	db := openTestDB(t)
+	pvzID := createTestPVZ(t, db)
+
+	reception, err := postgres.NewReceptionRepository(db, queryTimeout).Create(ctx, pvzID, auth.DummyEmployeeID)
+	require.NoError(t, err)
+
+	const workers = 50
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+
+			if i == workers/2 {
+				_, err := postgres.NewReceptionRepository(db, queryTimeout).CloseLast(ctx, pvzID, auth.DummyEmployeeID)
+				require.NoError(t, err)
+				return
+			}
+			if _, err := postgres.NewProductRepository(db, queryTimeout).Add(ctx, pvzID, "обувь", auth.DummyEmployeeID); err != nil {
+				require.ErrorIs(t, err, repository.ErrNoOpenReception)
+			}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	// Every product write must have finished before the close was recorded.
+	var late int
+	require.NoError(t, db.QueryRow(`
+        SELECT count(*)
+        FROM audit_log a
+        JOIN products p ON p.id = a.entity_id
+        WHERE a.action = $1 AND p.reception_id = $2
+          AND a.id > (SELECT id FROM audit_log WHERE action = $3 AND entity_id = $2)
+    `, models.AuditProductAdd, reception.ID, models.AuditReceptionClose).Scan(&late))
+	require.Zero(t, late)
+}
@@ -0,0 +1,198 @@
+package postgres
+
+import (
+	"avito-internship/internal/models"
+	"avito-internship/internal/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type PVZRepository struct {
+	store
+}
+
+func NewPVZRepository(db *sql.DB, timeout time.Duration) *PVZRepository {
+	return &PVZRepository{store{db: db, timeout: timeout}}
+}
+
+func (r *PVZRepository) Create(ctx context.Context, pvz models.PVZ, actorID string) (*models.PVZ, error) {
+	query := `
+		INSERT INTO pvz (id, registration_date, city)
+		VALUES ($1, $2, $3)
+		RETURNING id, registration_date, city
+	`
+
+	var newPVZ models.PVZ
+	err := r.withTx(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		row := tx.QueryRowContext(ctx, query, pvz.ID, pvz.RegistrationDate, pvz.City)
+		if err := row.Scan(&newPVZ.ID, &newPVZ.RegistrationDate, &newPVZ.City); err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+				return repository.ErrPVZExists
+			}
+			return err
+		}
+
+		return writeAudit(ctx, tx, actorID, models.AuditPVZCreate, "pvz", newPVZ.ID, newPVZ.ID, nil, models.NewPVZSnapshot(newPVZ))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &newPVZ, nil
+}
+
+// receptionFilterSQL selects receptions, aliased r, matching the dates,
+// status and product type of a repository.PVZFilter. It takes parameters
+// $1-$4 in the order of receptionFilterArgs.
+const receptionFilterSQL = `
+		($1::timestamptz IS NULL OR r.date_time >= $1::timestamptz)
+		AND ($2::timestamptz IS NULL OR r.date_time <= $2::timestamptz)
+		AND ($3::text = '' OR r.status = $3::text)
+		AND ($4::text = '' OR EXISTS (
+			SELECT 1 FROM products fp
+			WHERE fp.reception_id = r.id AND fp.deleted_at IS NULL AND fp.type = $4::text
+		))`
+
+// pvzFilterSQL selects rows of the unaliased pvz table matching a
+// repository.PVZFilter. It takes parameters $1-$5 in the order of
+// pvzFilterArgs.
+const pvzFilterSQL = `
+		($1::timestamptz IS NULL OR registration_date >= $1::timestamptz)
+		AND ($2::timestamptz IS NULL OR registration_date <= $2::timestamptz)
+		AND (COALESCE(cardinality($5::text[]), 0) = 0 OR city = ANY($5::text[]))
+		AND (($3::text = '' AND $4::text = '') OR EXISTS (
+			SELECT 1 FROM receptions r
+			WHERE r.pvz_id = pvz.id AND ` + receptionFilterSQL + `
+		))`
+
+func receptionFilterArgs(f repository.PVZFilter) []interface{} {
+	return []interface{}{f.StartDate, f.EndDate, f.ReceptionStatus, f.ProductType}
+}
+
+func pvzFilterArgs(f repository.PVZFilter) []interface{} {
+	return append(receptionFilterArgs(f), pq.Array(f.Cities))
+}
+
+func (r *PVZRepository) List(ctx context.Context, filter repository.PVZFilter, limit, offset int) (_ []models.PVZ, err error) {
+	ctx, done := r.query(ctx)
+	defer done(&err)
+
+	query := `
+		SELECT id, registration_date, city
+		FROM pvz
+		WHERE ` + pvzFilterSQL + `
+		ORDER BY registration_date DESC, id DESC
+		LIMIT $6 OFFSET $7
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, append(pvzFilterArgs(filter), limit, offset)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPVZs(rows)
+}
+
+func (r *PVZRepository) ListAfter(ctx context.Context, filter repository.PVZFilter, after *repository.PVZCursor, limit int) (_ []models.PVZ, err error) {
+	ctx, done := r.query(ctx)
+	defer done(&err)
+
+	query := `
+		SELECT id, registration_date, city
+		FROM pvz
+		WHERE ` + pvzFilterSQL + `
+		  AND ($6::timestamptz IS NULL OR (registration_date, id) < ($6::timestamptz, $7::uuid))
+		ORDER BY registration_date DESC, id DESC
+		LIMIT $8
+	`
+
+	var afterDate *time.Time
+	var afterID *string
+	if after != nil {
+		afterDate, afterID = &after.RegistrationDate, &after.ID
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, append(pvzFilterArgs(filter), afterDate, afterID, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPVZs(rows)
+}
+
+func scanPVZs(rows *sql.Rows) ([]models.PVZ, error) {
+	var list []models.PVZ
+	for rows.Next() {
+		var pvz models.PVZ
+		if err := rows.Scan(&pvz.ID, &pvz.RegistrationDate, &pvz.City); err != nil {
+			return nil, err
+		}
+		list = append(list, pvz)
+	}
+
+	return list, rows.Err()
+}
+
+func (r *PVZRepository) Count(ctx context.Context, filter repository.PVZFilter) (_ int, err error) {
+	ctx, done := r.query(ctx)
+	defer done(&err)
+
+	var count int
+	err = r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM pvz
+		WHERE `+pvzFilterSQL, pvzFilterArgs(filter)...).Scan(&count)
+
+	return count, err
+}
+
+func (r *PVZRepository) Stats(ctx context.Context, pvzIDs []string, filter repository.PVZFilter) (_ map[string]models.PVZStats, err error) {
+	ctx, done := r.query(ctx)
+	defer done(&err)
+
+	// The (pvz_id) grouping set yields the reception count, the
+	// (pvz_id, type) one the product count per type.
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT r.pvz_id, GROUPING(p.type) = 1, p.type, COUNT(DISTINCT r.id), COUNT(p.id)
+		FROM receptions r
+		LEFT JOIN products p ON p.reception_id = r.id AND p.deleted_at IS NULL
+		  AND ($4::text = '' OR p.type = $4::text)
+		WHERE r.pvz_id = ANY($5) AND `+receptionFilterSQL+`
+		GROUP BY GROUPING SETS ((r.pvz_id), (r.pvz_id, p.type))
+	`, append(receptionFilterArgs(filter), pq.Array(pvzIDs))...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := map[string]models.PVZStats{}
+	for rows.Next() {
+		var pvzID string
+		var total bool
+		var productType sql.NullString
+		var receptions, products int
+		if err := rows.Scan(&pvzID, &total, &productType, &receptions, &products); err != nil {
+			return nil, err
+		}
+
+		st, ok := stats[pvzID]
+		if !ok {
+			st.ProductCounts = map[string]int{}
+		}
+		if total {
+			st.ReceptionCount = receptions
+		} else if productType.Valid {
+			st.ProductCounts[productType.String] = products
+		}
+		stats[pvzID] = st
+	}
+
+	return stats, rows.Err()
+}
@@ -0,0 +1,229 @@
+package postgres_test
+
+import (
+	"avito-internship/internal/models"
+	"avito-internship/internal/repository"
+	"avito-internship/internal/repository/postgres"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPVZCreate_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	pvz := models.PVZ{
+		ID:               "test-id-1",
+		RegistrationDate: time.Now(),
+		City:             "Москва",
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO pvz`).
+		WithArgs(pvz.ID, pvz.RegistrationDate, pvz.City).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}).
+			AddRow(pvz.ID, pvz.RegistrationDate, pvz.City))
+	mock.ExpectExec(`INSERT INTO audit_log`).
+		WithArgs("user-1", models.AuditPVZCreate, "pvz", pvz.ID, pvz.ID, nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	answer, err := postgres.NewPVZRepository(db, queryTimeout).Create(ctx, pvz, "user-1")
+	assert.NoError(t, err)
+	assert.Equal(t, pvz.ID, answer.ID)
+	assert.Equal(t, pvz.City, answer.City)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPVZCreate_DBError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	pvz := models.PVZ{
+		ID:               "test-id-1",
+		RegistrationDate: time.Now(),
+		City:             "Москва",
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO pvz`).
+		WithArgs(pvz.ID, pvz.RegistrationDate, pvz.City).
+		WillReturnError(errors.New("insert failed"))
+	mock.ExpectRollback()
+
+	answer, err := postgres.NewPVZRepository(db, queryTimeout).Create(ctx, pvz, "user-1")
+	assert.Nil(t, answer)
+	assert.Error(t, err)
+	assert.Equal(t, "insert failed", err.Error())
+}
+
+func TestPVZList_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	startDate := time.Now().Add(-24 * time.Hour)
+	endDate := time.Now()
+	limit := 5
+	offset := 10
+
+	filter := repository.PVZFilter{
+		StartDate:       &startDate,
+		EndDate:         &endDate,
+		Cities:          []string{"Москва", "Казань"},
+		ReceptionStatus: "in_progress",
+		ProductType:     "электроника",
+	}
+
+	mock.ExpectQuery(`SELECT id, registration_date, city FROM pvz`).
+		WithArgs(startDate, endDate, "in_progress", "электроника", pq.Array(filter.Cities), limit, offset).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}).
+			AddRow("pvz-id", time.Now(), "Москва"))
+
+	answer, err := postgres.NewPVZRepository(db, queryTimeout).List(ctx, filter, limit, offset)
+	assert.NoError(t, err)
+	assert.Len(t, answer, 1)
+	assert.Equal(t, "Москва", answer[0].City)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPVZList_DBError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT id, registration_date, city FROM pvz`).
+		WithArgs(nil, nil, "", "", nil, 5, 0).
+		WillReturnError(errors.New("query error"))
+
+	answer, err := postgres.NewPVZRepository(db, queryTimeout).List(ctx, repository.PVZFilter{}, 5, 0)
+	assert.Nil(t, answer)
+	assert.EqualError(t, err, "query error")
+}
+
+func TestPVZListAfter_Cursor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	after := repository.PVZCursor{RegistrationDate: time.Now(), ID: "33333333-3333-3333-3333-333333333333"}
+
+	mock.ExpectQuery(`SELECT id, registration_date, city FROM pvz .* \(registration_date, id\) <`).
+		WithArgs(nil, nil, "", "", nil, after.RegistrationDate, after.ID, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}).
+			AddRow("22222222-2222-2222-2222-222222222222", after.RegistrationDate, "Казань"))
+
+	answer, err := postgres.NewPVZRepository(db, queryTimeout).ListAfter(ctx, repository.PVZFilter{}, &after, 3)
+	assert.NoError(t, err)
+	assert.Len(t, answer, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPVZListAfter_FirstPage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT id, registration_date, city FROM pvz`).
+		WithArgs(nil, nil, "", "", nil, nil, nil, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}))
+
+	answer, err := postgres.NewPVZRepository(db, queryTimeout).ListAfter(ctx, repository.PVZFilter{}, nil, 3)
+	assert.NoError(t, err)
+	assert.Empty(t, answer)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPVZCount(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM pvz`).
+		WithArgs(nil, nil, "", "", pq.Array([]string{"Казань"})).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(17))
+
+	count, err := postgres.NewPVZRepository(db, queryTimeout).Count(ctx, repository.PVZFilter{Cities: []string{"Казань"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 17, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPVZStats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	ids := []string{"pvz-1", "pvz-2"}
+	mock.ExpectQuery(`GROUP BY GROUPING SETS`).
+		WithArgs(nil, nil, "", "", pq.Array(ids)).
+		WillReturnRows(sqlmock.NewRows([]string{"pvz_id", "total", "type", "receptions", "products"}).
+			AddRow("pvz-1", true, nil, 2, 3).
+			AddRow("pvz-1", false, "обувь", 1, 2).
+			AddRow("pvz-1", false, "одежда", 1, 1).
+			AddRow("pvz-2", true, nil, 1, 0).
+			AddRow("pvz-2", false, nil, 1, 0))
+
+	stats, err := postgres.NewPVZRepository(db, queryTimeout).Stats(ctx, ids, repository.PVZFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, stats["pvz-1"].ReceptionCount)
+	assert.Equal(t, map[string]int{"обувь": 2, "одежда": 1}, stats["pvz-1"].ProductCounts)
+	assert.Equal(t, 1, stats["pvz-2"].ReceptionCount)
+	assert.Empty(t, stats["pvz-2"].ProductCounts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReceptionListByPVZs_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	startDate := time.Now().Add(-24 * time.Hour)
+	endDate := time.Now()
+
+	mock.ExpectQuery(`SELECT r.id, r.pvz_id, r.date_time, r.status, r.created_by, r.closed_by, r.closed_at FROM receptions r`).
+		WithArgs(startDate, endDate, "close", "", pq.Array([]string{"pvz-id", "pvz-id-2"})).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "pvz_id", "date_time", "status", "created_by", "closed_by", "closed_at"}).
+			AddRow("rec-id", "pvz-id", time.Now(), "in_progress", "user-1", nil, nil))
+
+	receptions, err := postgres.NewReceptionRepository(db, queryTimeout).ListByPVZs(ctx, []string{"pvz-id", "pvz-id-2"}, repository.PVZFilter{
+		StartDate:       &startDate,
+		EndDate:         &endDate,
+		ReceptionStatus: "close",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, receptions, 1)
+	assert.Equal(t, "pvz-id", receptions[0].PVZID)
+	assert.Equal(t, "user-1", *receptions[0].CreatedBy)
+	assert.Nil(t, receptions[0].ClosedBy)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductListByReceptions_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	deletedAt := time.Now()
+	mock.ExpectQuery(`SELECT id, reception_id, date_time, type, created_by, deleted_by, deleted_at FROM products`).
+		WithArgs(pq.Array([]string{"rec-id"}), "", false).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "reception_id", "date_time", "type", "created_by", "deleted_by", "deleted_at"}).
+			AddRow("prod-id", "rec-id", time.Now(), "электроника", "user-1", nil, nil).
+			AddRow("prod-id-2", "rec-id", time.Now(), "обувь", "user-1", "user-2", deletedAt))
+
+	products, err := postgres.NewProductRepository(db, queryTimeout).ListByReceptions(ctx, []string{"rec-id"}, "", false)
+	assert.NoError(t, err)
+	assert.Len(t, products, 2)
+	assert.Equal(t, "rec-id", products[0].ReceptionID)
+	assert.Nil(t, products[0].DeletedBy)
+	assert.Equal(t, "user-2", *products[1].DeletedBy)
+	assert.True(t, deletedAt.Equal(*products[1].DeletedAt))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
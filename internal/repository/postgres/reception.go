@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"avito-internship/internal/models"
+	"avito-internship/internal/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	uniqueViolation    = "23505"
+	openReceptionIndex = "receptions_one_open_per_pvz"
+)
+
+type ReceptionRepository struct {
+	store
+}
+
+func NewReceptionRepository(db *sql.DB, timeout time.Duration) *ReceptionRepository {
+	return &ReceptionRepository{store{db: db, timeout: timeout}}
+}
+
+// Create relies on the partial unique index receptions_one_open_per_pvz: the
+// insert itself is the check for an already open reception, so concurrent
+// calls cannot both succeed.
+func (r *ReceptionRepository) Create(ctx context.Context, pvzID, actorID string) (*models.Reception, error) {
+	var reception models.Reception
+	err := r.withTx(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		row := tx.QueryRowContext(ctx, `
+            INSERT INTO receptions (pvz_id, status, created_by)
+            VALUES ($1, 'in_progress', $2)
+            RETURNING id, date_time, status
+        `, pvzID, actorID)
+		if err := row.Scan(&reception.ID, &reception.DateTime, &reception.Status); err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation && pqErr.Constraint == openReceptionIndex {
+				return repository.ErrReceptionAlreadyOpen
+			}
+			if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+				return repository.ErrPVZNotFound
+			}
+			return err
+		}
+
+		reception.PVZID = pvzID
+		reception.CreatedBy = &actorID
+
+		return writeAudit(ctx, tx, actorID, models.AuditReceptionCreate, "reception", reception.ID, pvzID, nil, models.NewReceptionSnapshot(reception))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &reception, nil
+}
+
+func (r *ReceptionRepository) CloseLast(ctx context.Context, pvzID, actorID string) (*models.Reception, error) {
+	var after models.Reception
+	err := r.withTx(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, `
+            UPDATE receptions
+            SET status = 'close', closed_by = $2, closed_at = now()
+            WHERE id = (
+                SELECT id FROM receptions
+                WHERE pvz_id = $1 AND status = 'in_progress'
+                ORDER BY date_time DESC
+                LIMIT 1
+            )
+            RETURNING id, date_time, status, pvz_id, created_by, closed_by, closed_at
+        `, pvzID, actorID).Scan(&after.ID, &after.DateTime, &after.Status, &after.PVZID, &after.CreatedBy, &after.ClosedBy, &after.ClosedAt)
+		if err == sql.ErrNoRows {
+			return repository.ErrNoOpenReception
+		} else if err != nil {
+			return err
+		}
+
+		// Only the closing fields change, so the previous state is derived
+		// from the updated row.
+		before := after
+		before.Status = "in_progress"
+		before.ClosedBy = nil
+		before.ClosedAt = nil
+
+		return writeAudit(ctx, tx, actorID, models.AuditReceptionClose, "reception", after.ID, pvzID,
+			models.NewReceptionSnapshot(before), models.NewReceptionSnapshot(after))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &after, nil
+}
+
+func (r *ReceptionRepository) ListByPVZs(ctx context.Context, pvzIDs []string, filter repository.PVZFilter) (_ []models.Reception, err error) {
+	ctx, done := r.query(ctx)
+	defer done(&err)
+
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT r.id, r.pvz_id, r.date_time, r.status, r.created_by, r.closed_by, r.closed_at
+        FROM receptions r
+        WHERE r.pvz_id = ANY($5) AND `+receptionFilterSQL+`
+        ORDER BY r.date_time
+    `, append(receptionFilterArgs(filter), pq.Array(pvzIDs))...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var receptions []models.Reception
+	for rows.Next() {
+		var rec models.Reception
+		if err := rows.Scan(&rec.ID, &rec.PVZID, &rec.DateTime, &rec.Status, &rec.CreatedBy, &rec.ClosedBy, &rec.ClosedAt); err != nil {
+			return nil, err
+		}
+		receptions = append(receptions, rec)
+	}
+
+	return receptions, rows.Err()
+}
+
+// lockOpenReception returns the open reception of the PVZ and holds a row lock
+// on it until the transaction ends. Product changes take this lock so they
+// cannot interleave with CloseLast, whose UPDATE waits on the same row.
+func lockOpenReception(ctx context.Context, tx *sql.Tx, pvzID string) (string, error) {
+	var receptionID string
+	err := tx.QueryRowContext(ctx, `
+        SELECT id FROM receptions
+        WHERE pvz_id = $1 AND status = 'in_progress'
+        ORDER BY date_time DESC
+        LIMIT 1
+        FOR UPDATE
+    `, pvzID).Scan(&receptionID)
+	if err == sql.ErrNoRows {
+		return "", repository.ErrNoOpenReception
+	}
+
+	return receptionID, err
+}
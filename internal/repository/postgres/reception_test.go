@@ -0,0 +1,206 @@
+package postgres_test
+
+import (
+	"avito-internship/internal/auth"
+	"avito-internship/internal/models"
+	"avito-internship/internal/repository"
+	"avito-internship/internal/repository/postgres"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var closeReceptionColumns = []string{"id", "date_time", "status", "pvz_id", "created_by", "closed_by", "closed_at"}
+
+func TestReceptionCreate_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	pvzID := "pvz-123"
+
+	mock.ExpectBegin()
+	now := time.Now()
+	mock.ExpectQuery(`INSERT INTO receptions`).
+		WithArgs(pvzID, "user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "status"}).
+			AddRow("rec-id", now, "in_progress"))
+	mock.ExpectExec(`INSERT INTO audit_log`).
+		WithArgs("user-1", models.AuditReceptionCreate, "reception", "rec-id", pvzID, nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	r, err := postgres.NewReceptionRepository(db, queryTimeout).Create(ctx, pvzID, "user-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "in_progress", r.Status)
+	assert.Equal(t, pvzID, r.PVZID)
+	assert.Equal(t, "rec-id", r.ID)
+	assert.Equal(t, "user-1", *r.CreatedBy)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReceptionCreate_AlreadyExists(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	pvzID := "pvz-123"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO receptions`).
+		WithArgs(pvzID, "user-1").
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "receptions_one_open_per_pvz"})
+	mock.ExpectRollback()
+
+	r, err := postgres.NewReceptionRepository(db, queryTimeout).Create(ctx, pvzID, "user-1")
+	assert.Nil(t, r)
+	assert.ErrorIs(t, err, repository.ErrReceptionAlreadyOpen)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReceptionCreate_DBError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	pvzID := "pvz-123"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO receptions`).
+		WithArgs(pvzID, "user-1").
+		WillReturnError(errors.New("db failure"))
+	mock.ExpectRollback()
+
+	r, err := postgres.NewReceptionRepository(db, queryTimeout).Create(ctx, pvzID, "user-1")
+	assert.Nil(t, r)
+	assert.EqualError(t, err, "db failure")
+}
+
+func TestReceptionCreate_AuditError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	pvzID := "pvz-123"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO receptions`).
+		WithArgs(pvzID, "user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "status"}).
+			AddRow("rec-id", time.Now(), "in_progress"))
+	mock.ExpectExec(`INSERT INTO audit_log`).
+		WillReturnError(errors.New("audit failed"))
+	mock.ExpectRollback()
+
+	r, err := postgres.NewReceptionRepository(db, queryTimeout).Create(ctx, pvzID, "user-1")
+	assert.Nil(t, r)
+	assert.EqualError(t, err, "audit failed")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReceptionCreate_Concurrent(t *testing.T) {
+	db := openTestDB(t)
+	pvzID := createTestPVZ(t, db)
+
+	const workers = 50
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+		failures  []error
+	)
+
+	start := make(chan struct{})
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+
+			_, err := postgres.NewReceptionRepository(db, queryTimeout).Create(ctx, pvzID, auth.DummyEmployeeID)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				succeeded++
+			} else {
+				failures = append(failures, err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	require.Equal(t, 1, succeeded)
+	for _, err := range failures {
+		assert.ErrorIs(t, err, repository.ErrReceptionAlreadyOpen)
+	}
+
+	var open int
+	require.NoError(t, db.QueryRow(`
+        SELECT count(*) FROM receptions WHERE pvz_id = $1 AND status = 'in_progress'
+    `, pvzID).Scan(&open))
+	assert.Equal(t, 1, open)
+}
+
+func TestReceptionCloseLast_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	pvzID := "pvz-123"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE receptions`).
+		WithArgs(pvzID, "user-1").
+		WillReturnRows(sqlmock.NewRows(closeReceptionColumns).
+			AddRow("rec-id", time.Now(), "close", pvzID, "user-2", "user-1", time.Now()))
+	mock.ExpectExec(`INSERT INTO audit_log`).
+		WithArgs("user-1", models.AuditReceptionClose, "reception", "rec-id", pvzID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	_, err = postgres.NewReceptionRepository(db, queryTimeout).CloseLast(ctx, pvzID, "user-1")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReceptionCloseLast_NoActive(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	pvzID := "pvz-123"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE receptions`).
+		WithArgs(pvzID, "user-1").
+		WillReturnRows(sqlmock.NewRows(closeReceptionColumns))
+	mock.ExpectRollback()
+
+	_, err = postgres.NewReceptionRepository(db, queryTimeout).CloseLast(ctx, pvzID, "user-1")
+	assert.ErrorIs(t, err, repository.ErrNoOpenReception)
+}
+
+func TestReceptionCloseLast_DBError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	pvzID := "pvz-123"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE receptions`).
+		WithArgs(pvzID, "user-1").
+		WillReturnError(errors.New("update failed"))
+	mock.ExpectRollback()
+
+	_, err = postgres.NewReceptionRepository(db, queryTimeout).CloseLast(ctx, pvzID, "user-1")
+	assert.EqualError(t, err, "update failed")
+}
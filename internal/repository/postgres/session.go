@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"avito-internship/internal/models"
+	"avito-internship/internal/repository"
+	"context"
+	"database/sql"
+	"time"
+)
+
+type SessionRepository struct {
+	store
+}
+
+func NewSessionRepository(db *sql.DB, timeout time.Duration) *SessionRepository {
+	return &SessionRepository{store{db: db, timeout: timeout}}
+}
+
+func (r *SessionRepository) Create(ctx context.Context, session models.Session, refreshTokenHash string) (_ *models.Session, err error) {
+	ctx, done := r.query(ctx)
+	defer done(&err)
+
+	row := r.db.QueryRowContext(ctx, `
+        INSERT INTO sessions (user_id, refresh_token_hash, expires_at)
+        VALUES ($1, $2, $3)
+        RETURNING id
+    `, session.UserID, refreshTokenHash, session.ExpiresAt)
+	if err := row.Scan(&session.ID); err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (r *SessionRepository) Rotate(ctx context.Context, refreshTokenHash, newHash string, expiresAt time.Time) (_ *models.Session, err error) {
+	ctx, done := r.query(ctx)
+	defer done(&err)
+
+	session := models.Session{ExpiresAt: expiresAt}
+
+	err = r.db.QueryRowContext(ctx, `
+        UPDATE sessions s
+        SET refresh_token_hash = $2, expires_at = $3
+        FROM users u
+        WHERE s.refresh_token_hash = $1
+          AND s.revoked_at IS NULL
+          AND s.expires_at > now()
+          AND u.id = s.user_id
+        RETURNING s.id, s.user_id, u.role
+    `, refreshTokenHash, newHash, expiresAt).Scan(&session.ID, &session.UserID, &session.Role)
+	if err == sql.ErrNoRows {
+		return nil, repository.ErrSessionNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (r *SessionRepository) Revoke(ctx context.Context, sessionID string) (err error) {
+	ctx, done := r.query(ctx)
+	defer done(&err)
+
+	_, err = r.db.ExecContext(ctx, `
+        UPDATE sessions
+        SET revoked_at = now()
+        WHERE id = $1 AND revoked_at IS NULL
+    `, sessionID)
+	return err
+}
+
+func (r *SessionRepository) RevokeByUser(ctx context.Context, userID string) (_ int64, err error) {
+	ctx, done := r.query(ctx)
+	defer done(&err)
+
+	res, err := r.db.ExecContext(ctx, `
+        UPDATE sessions
+        SET revoked_at = now()
+        WHERE user_id = $1 AND revoked_at IS NULL
+    `, userID)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *SessionRepository) IsActive(ctx context.Context, sessionID string) (_ bool, err error) {
+	ctx, done := r.query(ctx)
+	defer done(&err)
+
+	var active bool
+	err = r.db.QueryRowContext(ctx, `
+        SELECT revoked_at IS NULL AND expires_at > now()
+        FROM sessions
+        WHERE id = $1
+    `, sessionID).Scan(&active)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return active, nil
+}
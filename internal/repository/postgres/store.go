@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// store is the database handle the repositories share.
+type store struct {
+	db *sql.DB
+	// timeout bounds every query, or every transaction as a whole, on top of
+	// the caller's deadline. Zero leaves only the caller's deadline.
+	timeout time.Duration
+}
+
+// query bounds ctx by the query timeout. The returned func must be deferred
+// with a pointer to the caller's error: besides releasing the timer, it
+// reports a query aborted because ctx is done as ctx.Err() instead of the
+// driver's "canceling statement" error, so that callers can tell timeouts
+// and cancellation from database failures.
+func (s store) query(ctx context.Context) (context.Context, func(*error)) {
+	cancel := context.CancelFunc(func() {})
+	if s.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+	}
+
+	return ctx, func(err *error) {
+		if *err != nil && ctx.Err() != nil {
+			*err = ctx.Err()
+		}
+		cancel()
+	}
+}
+
+// withTx runs fn in a transaction, rolling back if it returns an error. fn
+// must run its queries with the ctx it is given.
+func (s store) withTx(ctx context.Context, fn func(ctx context.Context, tx *sql.Tx) error) (err error) {
+	ctx, done := s.query(ctx)
+	defer done(&err)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(ctx, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
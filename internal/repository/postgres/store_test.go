@@ -0,0 +1,56 @@
+package postgres_test
+
+import (
+	"avito-internship/internal/models"
+	"avito-internship/internal/repository"
+	"avito-internship/internal/repository/postgres"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryTimeout(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT COUNT`).
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	_, err = postgres.NewPVZRepository(db, 10*time.Millisecond).Count(ctx, repository.PVZFilter{})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestQueryTimeout_Transaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO pvz`).
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}))
+
+	_, err = postgres.NewPVZRepository(db, 10*time.Millisecond).Create(ctx, models.PVZ{City: "Казань"}, "user-1")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestQueryCanceled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT COUNT`).
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	canceled, cancel := context.WithCancel(ctx)
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	_, err = postgres.NewPVZRepository(db, queryTimeout).Count(canceled, repository.PVZFilter{})
+	require.ErrorIs(t, err, context.Canceled)
+}
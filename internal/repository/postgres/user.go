@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"avito-internship/internal/models"
+	"avito-internship/internal/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type UserRepository struct {
+	store
+}
+
+func NewUserRepository(db *sql.DB, timeout time.Duration) *UserRepository {
+	return &UserRepository{store{db: db, timeout: timeout}}
+}
+
+func (r *UserRepository) Create(ctx context.Context, user models.User) (_ *models.User, err error) {
+	ctx, done := r.query(ctx)
+	defer done(&err)
+
+	row := r.db.QueryRowContext(ctx, `
+        INSERT INTO users (email, password_hash, role)
+        VALUES ($1, $2, $3)
+        RETURNING id
+    `, user.Email, user.PasswordHash, user.Role)
+	if err := row.Scan(&user.ID); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return nil, repository.ErrUserExists
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	return r.get(ctx, `
+        SELECT id, email, password_hash, role
+        FROM users
+        WHERE email = $1
+    `, email)
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
+	return r.get(ctx, `
+        SELECT id, email, password_hash, role
+        FROM users
+        WHERE id = $1
+    `, id)
+}
+
+func (r *UserRepository) get(ctx context.Context, query string, arg string) (_ *models.User, err error) {
+	ctx, done := r.query(ctx)
+	defer done(&err)
+
+	var user models.User
+	err = r.db.QueryRowContext(ctx, query, arg).Scan(&user.ID, &user.Email, &user.PasswordHash, &user.Role)
+	if err == sql.ErrNoRows {
+		return nil, repository.ErrUserNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"avito-internship/internal/models"
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrReceptionAlreadyOpen = errors.New("reception already open")
+	ErrNoOpenReception      = errors.New("no open reception")
+	ErrNoProducts           = errors.New("no products in reception")
+	ErrUserExists           = errors.New("user already exists")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrPVZExists            = errors.New("pvz already exists")
+	ErrPVZNotFound          = errors.New("pvz not found")
+	ErrSessionNotFound      = errors.New("session not found")
+	ErrNotAssigned          = errors.New("employee is not assigned")
+)
+
+// Mutating methods write their audit entry atomically with the change, so an
+// implementation must either apply both or neither.
+//
+// Every method stops waiting for the database once ctx is done and then
+// returns ctx.Err().
+
+// PVZFilter narrows the PVZ listing. Dates bound the PVZ registration date
+// and the receptions shown with it. ReceptionStatus and ProductType keep only
+// PVZs having a matching reception and only such receptions in the nested
+// lists; ProductType ignores deleted products. Empty fields match everything.
+type PVZFilter struct {
+	StartDate       *time.Time
+	EndDate         *time.Time
+	Cities          []string
+	ReceptionStatus string
+	ProductType     string
+	// IncludeDeleted lists deleted products along with the others. It does not
+	// change which PVZs match or how products are counted.
+	IncludeDeleted bool
+}
+
+// PVZCursor is the position of a PVZ in the list order: registration date
+// descending, then id descending.
+type PVZCursor struct {
+	RegistrationDate time.Time
+	ID               string
+}
+
+type PVZRepository interface {
+	Create(ctx context.Context, pvz models.PVZ, actorID string) (*models.PVZ, error)
+	// List returns PVZs matching the filter, newest first.
+	List(ctx context.Context, filter PVZFilter, limit, offset int) ([]models.PVZ, error)
+	// ListAfter returns PVZs in the same order as List that come strictly after
+	// the cursor, or from the start when it is nil.
+	ListAfter(ctx context.Context, filter PVZFilter, after *PVZCursor, limit int) ([]models.PVZ, error)
+	// Count returns how many PVZs List would return without paging.
+	Count(ctx context.Context, filter PVZFilter) (int, error)
+	// Stats aggregates the receptions and products matching the filter for
+	// each of the given PVZs. PVZs without such receptions are missing from
+	// the result.
+	Stats(ctx context.Context, pvzIDs []string, filter PVZFilter) (map[string]models.PVZStats, error)
+}
+
+type ReceptionRepository interface {
+	// Create opens a reception and fails with ErrReceptionAlreadyOpen if the
+	// PVZ already has one in progress.
+	Create(ctx context.Context, pvzID, actorID string) (*models.Reception, error)
+	// CloseLast closes the open reception of the PVZ or fails with
+	// ErrNoOpenReception.
+	CloseLast(ctx context.Context, pvzID, actorID string) (*models.Reception, error)
+	// ListByPVZs returns receptions of all the given PVZs matching the filter
+	// in a single round trip, oldest first.
+	ListByPVZs(ctx context.Context, pvzIDs []string, filter PVZFilter) ([]models.Reception, error)
+}
+
+type ProductRepository interface {
+	// Add puts a product into the open reception of the PVZ or fails with
+	// ErrNoOpenReception. The reception cannot be closed while Add runs.
+	Add(ctx context.Context, pvzID, productType, actorID string) (*models.Product, error)
+	// DeleteLast soft-deletes the newest product of the open reception and
+	// fails with ErrNoOpenReception or ErrNoProducts.
+	DeleteLast(ctx context.Context, pvzID, actorID string) (*models.Product, error)
+	// ListByReceptions returns the products of all the given receptions in a
+	// single round trip, oldest first. Deleted products are left out unless
+	// includeDeleted is set. A non-empty productType keeps only products of
+	// that type.
+	ListByReceptions(ctx context.Context, receptionIDs []string, productType string, includeDeleted bool) ([]models.Product, error)
+}
+
+type UserRepository interface {
+	// Create stores a user and fails with ErrUserExists on a duplicate email.
+	Create(ctx context.Context, user models.User) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	GetByID(ctx context.Context, id string) (*models.User, error)
+}
+
+type SessionRepository interface {
+	Create(ctx context.Context, session models.Session, refreshTokenHash string) (*models.Session, error)
+	// Rotate replaces the refresh token of a live session and fails with
+	// ErrSessionNotFound if the old token is unknown, expired or revoked.
+	Rotate(ctx context.Context, refreshTokenHash, newHash string, expiresAt time.Time) (*models.Session, error)
+	Revoke(ctx context.Context, sessionID string) error
+	RevokeByUser(ctx context.Context, userID string) (int64, error)
+	IsActive(ctx context.Context, sessionID string) (bool, error)
+}
+
+type AssignmentRepository interface {
+	// Assign is idempotent and fails with ErrPVZNotFound for an unknown PVZ.
+	Assign(ctx context.Context, pvzID, userID string) error
+	// Unassign fails with ErrNotAssigned if there was nothing to remove.
+	Unassign(ctx context.Context, pvzID, userID string) error
+	ListEmployees(ctx context.Context, pvzID string) ([]models.User, error)
+	IsAssigned(ctx context.Context, userID, pvzID string) (bool, error)
+}
+
+type AuditFilter struct {
+	PVZID   string
+	ActorID string
+	From    *time.Time
+	To      *time.Time
+}
+
+type AuditRepository interface {
+	// List returns matching entries, newest first.
+	List(ctx context.Context, filter AuditFilter, limit, offset int) ([]models.AuditEntry, error)
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"avito-internship/internal/auth"
+	"avito-internship/internal/models"
+	"avito-internship/internal/repository"
+	"context"
+	"errors"
+)
+
+type AssignmentService struct {
+	users       repository.UserRepository
+	assignments repository.AssignmentRepository
+	policy      *auth.Policy
+}
+
+// NewAssignmentService only assigns users whose role the policy scopes to
+// PVZs.
+func NewAssignmentService(users repository.UserRepository, assignments repository.AssignmentRepository, policy *auth.Policy) *AssignmentService {
+	return &AssignmentService{users: users, assignments: assignments, policy: policy}
+}
+
+func (s *AssignmentService) AssignEmployee(ctx context.Context, pvzID, userID string) error {
+	user, err := s.users.GetByID(ctx, userID)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return ErrUserNotFound
+	} else if err != nil {
+		return err
+	}
+
+	if !s.policy.IsPVZScoped(user.Role) {
+		return ErrNotEmployee
+	}
+
+	err = s.assignments.Assign(ctx, pvzID, userID)
+	if errors.Is(err, repository.ErrPVZNotFound) {
+		return ErrPVZNotFound
+	}
+	return err
+}
+
+func (s *AssignmentService) UnassignEmployee(ctx context.Context, pvzID, userID string) error {
+	err := s.assignments.Unassign(ctx, pvzID, userID)
+	if errors.Is(err, repository.ErrNotAssigned) {
+		return ErrNotAssigned
+	}
+	return err
+}
+
+func (s *AssignmentService) GetPVZEmployees(ctx context.Context, pvzID string) ([]models.User, error) {
+	return s.assignments.ListEmployees(ctx, pvzID)
+}
+
+func (s *AssignmentService) IsEmployeeAssigned(ctx context.Context, userID, pvzID string) (bool, error) {
+	return s.assignments.IsAssigned(ctx, userID, pvzID)
+}
@@ -0,0 +1,93 @@
+package services_test
+
+import (
+	"avito-internship/internal/auth"
+	"avito-internship/internal/repository/postgres"
+	"avito-internship/internal/services"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAssignmentService(db *sql.DB) *services.AssignmentService {
+	return services.NewAssignmentService(postgres.NewUserRepository(db, time.Second), postgres.NewAssignmentRepository(db, time.Second), auth.NewPolicy(auth.DefaultPolicyConfig))
+}
+
+func TestAssignEmployee_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT id, email, password_hash, role FROM users`).
+		WithArgs("user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "password_hash", "role"}).
+			AddRow("user-1", "user@example.com", "hash", "employee"))
+	mock.ExpectExec(`INSERT INTO pvz_employees`).
+		WithArgs("pvz-1", "user-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, newAssignmentService(db).AssignEmployee(ctx, "pvz-1", "user-1"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAssignEmployee_UserNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT id, email, password_hash, role FROM users`).
+		WithArgs("user-1").
+		WillReturnError(sql.ErrNoRows)
+
+	assert.EqualError(t, newAssignmentService(db).AssignEmployee(ctx, "pvz-1", "user-1"), "User not found")
+}
+
+func TestAssignEmployee_PVZNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT id, email, password_hash, role FROM users`).
+		WithArgs("user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "password_hash", "role"}).
+			AddRow("user-1", "user@example.com", "hash", "employee"))
+	mock.ExpectExec(`INSERT INTO pvz_employees`).
+		WithArgs("pvz-1", "user-1").
+		WillReturnError(&pq.Error{Code: "23503"})
+
+	assert.EqualError(t, newAssignmentService(db).AssignEmployee(ctx, "pvz-1", "user-1"), "PVZ not found")
+}
+
+func TestGetPVZEmployees(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM pvz_employees`).
+		WithArgs("pvz-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "role"}).
+			AddRow("user-1", "user@example.com", "employee"))
+
+	users, err := newAssignmentService(db).GetPVZEmployees(ctx, "pvz-1")
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, "user@example.com", users[0].Email)
+}
+
+func TestIsEmployeeAssigned(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM pvz_employees`).
+		WithArgs("user-1", "pvz-1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	assigned, err := newAssignmentService(db).IsEmployeeAssigned(ctx, "user-1", "pvz-1")
+	assert.NoError(t, err)
+	assert.True(t, assigned)
+}
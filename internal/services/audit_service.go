@@ -0,0 +1,35 @@
+package services
+
+import (
+	"avito-internship/internal/models"
+	"avito-internship/internal/repository"
+	"context"
+	"time"
+)
+
+type AuditFilter struct {
+	PVZID   string
+	ActorID string
+	From    *time.Time
+	To      *time.Time
+	Page    int
+	Limit   int
+}
+
+type AuditService struct {
+	audit repository.AuditRepository
+}
+
+func NewAuditService(audit repository.AuditRepository) *AuditService {
+	return &AuditService{audit: audit}
+}
+
+func (s *AuditService) GetAuditLog(ctx context.Context, filter AuditFilter) ([]models.AuditEntry, error) {
+	offset := (filter.Page - 1) * filter.Limit
+	return s.audit.List(ctx, repository.AuditFilter{
+		PVZID:   filter.PVZID,
+		ActorID: filter.ActorID,
+		From:    filter.From,
+		To:      filter.To,
+	}, filter.Limit, offset)
+}
@@ -0,0 +1,45 @@
+package services_test
+
+import (
+	"avito-internship/internal/models"
+	"avito-internship/internal/repository/postgres"
+	"avito-internship/internal/services"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAuditService(db *sql.DB) *services.AuditService {
+	return services.NewAuditService(postgres.NewAuditRepository(db, time.Second))
+}
+
+func TestGetAuditLog_Filters(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	from := time.Now().Add(-time.Hour)
+	mock.ExpectQuery(`SELECT id, occurred_at, actor_id, action, entity_type, entity_id, pvz_id, before, after FROM audit_log`).
+		WithArgs("pvz-1", nil, from, nil, 10, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "occurred_at", "actor_id", "action", "entity_type", "entity_id", "pvz_id", "before", "after"}).
+			AddRow(int64(7), time.Now(), "user-1", models.AuditProductDelete, "product", "prod-1", "pvz-1",
+				[]byte(`{"id":"prod-1"}`), []byte(`{"id":"prod-1","deletedBy":"user-1"}`)).
+			AddRow(int64(6), time.Now(), "user-1", models.AuditProductAdd, "product", "prod-1", "pvz-1",
+				nil, []byte(`{"id":"prod-1"}`)))
+
+	entries, err := newAuditService(db).GetAuditLog(ctx, services.AuditFilter{
+		PVZID: "pvz-1",
+		From:  &from,
+		Page:  2,
+		Limit: 10,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "user-1", *entries[0].ActorID)
+	assert.JSONEq(t, `{"id":"prod-1","deletedBy":"user-1"}`, string(entries[0].After))
+	assert.Nil(t, entries[1].Before)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
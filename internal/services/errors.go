@@ -0,0 +1,34 @@
+package services
+
+// Error is a domain error. Code is stable and meant for clients to branch on;
+// Message is for humans and may change.
+type Error struct {
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+var (
+	ErrInvalidCredentials  = &Error{Code: "invalid_credentials", Message: "Invalid credentials"}
+	ErrInvalidRefreshToken = &Error{Code: "invalid_refresh_token", Message: "Invalid refresh token"}
+	ErrRoleNotAllowed      = &Error{Code: "role_not_allowed", Message: "Role not allowed"}
+	// ErrPasswordTooLong is reported as invalid input, like a request that
+	// fails binding.
+	ErrPasswordTooLong = &Error{Code: "invalid_input", Message: "Invalid input"}
+	ErrUserExists      = &Error{Code: "user_already_exists", Message: "User already exists"}
+	ErrUserNotFound    = &Error{Code: "user_not_found", Message: "User not found"}
+	ErrNotEmployee     = &Error{Code: "user_not_employee", Message: "User is not an employee"}
+	ErrNotAssigned     = &Error{Code: "employee_not_assigned", Message: "Employee is not assigned to this PVZ"}
+
+	ErrCityNotAllowed = &Error{Code: "city_not_allowed", Message: "City not allowed"}
+	ErrPVZExists      = &Error{Code: "pvz_already_exists", Message: "PVZ already exists"}
+	ErrPVZNotFound    = &Error{Code: "pvz_not_found", Message: "PVZ not found"}
+	ErrInvalidCursor  = &Error{Code: "invalid_cursor", Message: "Invalid cursor"}
+
+	ErrReceptionAlreadyOpen = &Error{Code: "reception_already_open", Message: "There is already an open reception"}
+	ErrNoActiveReception    = &Error{Code: "no_active_reception", Message: "No active reception"}
+	ErrNoProductsToDelete   = &Error{Code: "no_products_to_delete", Message: "No products to delete"}
+)
@@ -0,0 +1,98 @@
+package services_test
+
+import (
+	"avito-internship/internal/models"
+	"avito-internship/internal/repository"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+var ctx = context.Background()
+
+type mockPVZRepository struct {
+	mock.Mock
+}
+
+func (m *mockPVZRepository) Create(_ context.Context, pvz models.PVZ, actorID string) (*models.PVZ, error) {
+	args := m.Called(pvz, actorID)
+	if p := args.Get(0); p != nil {
+		return p.(*models.PVZ), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockPVZRepository) List(_ context.Context, filter repository.PVZFilter, limit, offset int) ([]models.PVZ, error) {
+	args := m.Called(filter, limit, offset)
+	list, _ := args.Get(0).([]models.PVZ)
+	return list, args.Error(1)
+}
+
+func (m *mockPVZRepository) ListAfter(_ context.Context, filter repository.PVZFilter, after *repository.PVZCursor, limit int) ([]models.PVZ, error) {
+	args := m.Called(filter, after, limit)
+	list, _ := args.Get(0).([]models.PVZ)
+	return list, args.Error(1)
+}
+
+func (m *mockPVZRepository) Count(_ context.Context, filter repository.PVZFilter) (int, error) {
+	args := m.Called(filter)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockPVZRepository) Stats(_ context.Context, pvzIDs []string, filter repository.PVZFilter) (map[string]models.PVZStats, error) {
+	args := m.Called(pvzIDs, filter)
+	stats, _ := args.Get(0).(map[string]models.PVZStats)
+	return stats, args.Error(1)
+}
+
+type mockReceptionRepository struct {
+	mock.Mock
+}
+
+func (m *mockReceptionRepository) Create(_ context.Context, pvzID, actorID string) (*models.Reception, error) {
+	args := m.Called(pvzID, actorID)
+	if r := args.Get(0); r != nil {
+		return r.(*models.Reception), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockReceptionRepository) CloseLast(_ context.Context, pvzID, actorID string) (*models.Reception, error) {
+	args := m.Called(pvzID, actorID)
+	if r := args.Get(0); r != nil {
+		return r.(*models.Reception), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockReceptionRepository) ListByPVZs(_ context.Context, pvzIDs []string, filter repository.PVZFilter) ([]models.Reception, error) {
+	args := m.Called(pvzIDs, filter)
+	list, _ := args.Get(0).([]models.Reception)
+	return list, args.Error(1)
+}
+
+type mockProductRepository struct {
+	mock.Mock
+}
+
+func (m *mockProductRepository) Add(_ context.Context, pvzID, productType, actorID string) (*models.Product, error) {
+	args := m.Called(pvzID, productType, actorID)
+	if p := args.Get(0); p != nil {
+		return p.(*models.Product), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockProductRepository) DeleteLast(_ context.Context, pvzID, actorID string) (*models.Product, error) {
+	args := m.Called(pvzID, actorID)
+	if p := args.Get(0); p != nil {
+		return p.(*models.Product), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockProductRepository) ListByReceptions(_ context.Context, receptionIDs []string, productType string, includeDeleted bool) ([]models.Product, error) {
+	args := m.Called(receptionIDs, productType, includeDeleted)
+	list, _ := args.Get(0).([]models.Product)
+	return list, args.Error(1)
+}
@@ -1,54 +1,46 @@
 package services
 
 import (
+	"avito-internship/internal/metrics"
 	"avito-internship/internal/models"
-	"database/sql"
+	"avito-internship/internal/repository"
+	"context"
 	"errors"
 )
 
-func AddProduct(db *sql.DB, pvzID, productType string) (*models.Product, error) {
-	var receptionID string
-	err := db.QueryRow(`
-        SELECT id FROM receptions
-        WHERE pvz_id = $1 AND status = 'in_progress'
-        ORDER BY date_time DESC
-        LIMIT 1
-    `, pvzID).Scan(&receptionID)
-
-	if err != nil {
-		return nil, errors.New("no active product")
-	}
+type ProductService struct {
+	products repository.ProductRepository
+}
 
-	var product models.Product
-	row := db.QueryRow(`
-        INSERT INTO products (type, reception_id)
-        VALUES ($1, $2)
-        RETURNING id, date_time
-    `, productType, receptionID)
+func NewProductService(products repository.ProductRepository) *ProductService {
+	return &ProductService{products: products}
+}
 
-	if err := row.Scan(&product.ID, &product.DateTime); err != nil {
+func (s *ProductService) AddProduct(ctx context.Context, pvzID, productType, actorID string) (*models.Product, error) {
+	product, err := s.products.Add(ctx, pvzID, productType, actorID)
+	if errors.Is(err, repository.ErrNoOpenReception) {
+		return nil, ErrNoActiveReception
+	}
+	if err != nil {
 		return nil, err
 	}
 
-	product.Type = productType
-	product.ReceptionID = receptionID
-	return &product, nil
+	metrics.ProductsAdded.WithLabelValues(product.Type).Inc()
+	return product, nil
 }
 
-func DeleteLastProduct(db *sql.DB, pvzID string) error {
-	var productID string
-	err := db.QueryRow(`
-        SELECT p.id FROM products p
-        JOIN receptions r ON r.id = p.reception_id
-        WHERE r.pvz_id = $1 AND r.status = 'in_progress'
-        ORDER BY p.date_time DESC
-        LIMIT 1
-    `, pvzID).Scan(&productID)
-
+func (s *ProductService) DeleteLastProduct(ctx context.Context, pvzID, actorID string) error {
+	product, err := s.products.DeleteLast(ctx, pvzID, actorID)
+	if errors.Is(err, repository.ErrNoOpenReception) {
+		return ErrNoActiveReception
+	}
+	if errors.Is(err, repository.ErrNoProducts) {
+		return ErrNoProductsToDelete
+	}
 	if err != nil {
-		return errors.New("no products to delete or no active receptions")
+		return err
 	}
 
-	_, err = db.Exec(`DELETE FROM products WHERE id = $1`, productID)
-	return err
+	metrics.ProductsDeleted.WithLabelValues(product.Type).Inc()
+	return nil
 }
@@ -1,98 +1,69 @@
 package services_test
 
 import (
+	"avito-internship/internal/metrics"
+	"avito-internship/internal/models"
+	"avito-internship/internal/repository"
 	"avito-internship/internal/services"
-	"database/sql"
+	"errors"
 	"testing"
-	"time"
 
-	"github.com/DATA-DOG/go-sqlmock"
-	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 )
 
 func TestAddProduct_Success(t *testing.T) {
-	sqlDB, mock, err := sqlmock.New()
-	require.NoError(t, err)
-	defer sqlDB.Close()
-
-	db := sqlx.NewDb(sqlDB, "postgres")
-	pvzID := "pvz-1"
-	receptionID := "rec-1"
-	now := time.Now()
+	repo := new(mockProductRepository)
+	repo.On("Add", "pvz-1", "обувь", "user-1").
+		Return(&models.Product{ID: "prod-1", Type: "обувь", ReceptionID: "rec-1"}, nil)
 
-	mock.ExpectQuery(`(?i)SELECT id FROM receptions.*status = 'in_progress'`).
-		WithArgs(pvzID).
-		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(receptionID))
+	added := testutil.ToFloat64(metrics.ProductsAdded.WithLabelValues("обувь"))
 
-	mock.ExpectQuery(`(?i)INSERT INTO products`).
-		WithArgs("обувь", receptionID).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time"}).AddRow("prod-1", now))
-
-	product, err := services.AddProduct(db.DB, pvzID, "обувь")
+	product, err := services.NewProductService(repo).AddProduct(ctx, "pvz-1", "обувь", "user-1")
 	require.NoError(t, err)
-	require.NotNil(t, product)
-	require.Equal(t, "обувь", product.Type)
-	require.Equal(t, receptionID, product.ReceptionID)
 	require.Equal(t, "prod-1", product.ID)
-	require.NoError(t, mock.ExpectationsWereMet())
+	require.Equal(t, added+1, testutil.ToFloat64(metrics.ProductsAdded.WithLabelValues("обувь")))
+	repo.AssertExpectations(t)
 }
 
 func TestAddProduct_NoReception(t *testing.T) {
-	sqlDB, mock, err := sqlmock.New()
-	require.NoError(t, err)
-	defer sqlDB.Close()
+	repo := new(mockProductRepository)
+	repo.On("Add", "pvz-1", "обувь", "user-1").Return(nil, repository.ErrNoOpenReception)
 
-	db := sqlx.NewDb(sqlDB, "postgres")
-	pvzID := "pvz-1"
-
-	mock.ExpectQuery(`(?i)SELECT id FROM receptions.*status = 'in_progress'`).
-		WithArgs(pvzID).
-		WillReturnError(sql.ErrNoRows)
-
-	product, err := services.AddProduct(db.DB, pvzID, "обувь")
-	require.Error(t, err)
+	product, err := services.NewProductService(repo).AddProduct(ctx, "pvz-1", "обувь", "user-1")
 	require.Nil(t, product)
-	require.EqualError(t, err, "no active product")
-	require.NoError(t, mock.ExpectationsWereMet())
+	require.ErrorIs(t, err, services.ErrNoActiveReception)
 }
 
 func TestDeleteLastProduct_Success(t *testing.T) {
-	sqlDB, mock, err := sqlmock.New()
-	require.NoError(t, err)
-	defer sqlDB.Close()
+	repo := new(mockProductRepository)
+	repo.On("DeleteLast", "pvz-1", "user-1").Return(&models.Product{ID: "prod-1", Type: "одежда"}, nil)
+	deleted := testutil.ToFloat64(metrics.ProductsDeleted.WithLabelValues("одежда"))
 
-	db := sqlx.NewDb(sqlDB, "postgres")
-	pvzID := "pvz-1"
-	productID := "prod-1"
-
-	mock.ExpectQuery(`(?i)SELECT p.id FROM products`).
-		WithArgs(pvzID).
-		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(productID))
-
-	mock.ExpectExec(`(?i)DELETE FROM products`).
-		WithArgs(productID).
-		WillReturnResult(sqlmock.NewResult(1, 1))
-
-	err = services.DeleteLastProduct(db.DB, pvzID)
+	err := services.NewProductService(repo).DeleteLastProduct(ctx, "pvz-1", "user-1")
 	require.NoError(t, err)
-	require.NoError(t, mock.ExpectationsWereMet())
+	require.Equal(t, deleted+1, testutil.ToFloat64(metrics.ProductsDeleted.WithLabelValues("одежда")))
+	repo.AssertExpectations(t)
 }
 
-func TestDeleteLastProduct_NoProduct(t *testing.T) {
-	sqlDB, mock, err := sqlmock.New()
-	require.NoError(t, err)
-	defer sqlDB.Close()
-
-	db := sqlx.NewDb(sqlDB, "postgres")
-	pvzID := "pvz-1"
+func TestDeleteLastProduct_NothingToDelete(t *testing.T) {
+	cases := map[error]error{
+		repository.ErrNoOpenReception: services.ErrNoActiveReception,
+		repository.ErrNoProducts:      services.ErrNoProductsToDelete,
+	}
+	for repoErr, want := range cases {
+		repo := new(mockProductRepository)
+		repo.On("DeleteLast", "pvz-1", "user-1").Return(nil, repoErr)
+
+		err := services.NewProductService(repo).DeleteLastProduct(ctx, "pvz-1", "user-1")
+		require.ErrorIs(t, err, want)
+	}
+}
 
-	mock.ExpectQuery(`(?i)SELECT p.id FROM products`).
-		WithArgs(pvzID).
-		WillReturnError(sql.ErrNoRows)
+func TestDeleteLastProduct_RepoError(t *testing.T) {
+	repo := new(mockProductRepository)
+	repo.On("DeleteLast", "pvz-1", "user-1").Return(nil, errors.New("update failed"))
 
-	err = services.DeleteLastProduct(db.DB, pvzID)
-	require.Error(t, err)
-	require.EqualError(t, err, "no products to delete or no active receptions")
-	require.NoError(t, mock.ExpectationsWereMet())
+	err := services.NewProductService(repo).DeleteLastProduct(ctx, "pvz-1", "user-1")
+	require.EqualError(t, err, "update failed")
 }
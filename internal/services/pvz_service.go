@@ -1,15 +1,39 @@
 package services
 
 import (
+	"avito-internship/internal/metrics"
 	"avito-internship/internal/models"
-	"database/sql"
+	"avito-internship/internal/repository"
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
-	"log"
+	"slices"
 	"time"
+
+	"github.com/google/uuid"
 )
 
-var allowedCities = map[string]bool{
-	"Москва": true, "Санкт-Петербург": true, "Казань": true,
+// PVZFilter selects PVZs for the listing; see repository.PVZFilter for the
+// semantics of each field.
+type PVZFilter struct {
+	StartDate       *time.Time
+	EndDate         *time.Time
+	Cities          []string
+	ReceptionStatus string
+	ProductType     string
+	IncludeDeleted  bool
+}
+
+func (f PVZFilter) toRepository() repository.PVZFilter {
+	return repository.PVZFilter{
+		StartDate:       f.StartDate,
+		EndDate:         f.EndDate,
+		Cities:          f.Cities,
+		ReceptionStatus: f.ReceptionStatus,
+		ProductType:     f.ProductType,
+		IncludeDeleted:  f.IncludeDeleted,
+	}
 }
 
 type PVZWithReceptions struct {
@@ -22,135 +46,226 @@ type ReceptionWithProducts struct {
 	Products  []models.Product
 }
 
-func CreatePVZ(db *sql.DB, pvz models.PVZ) (*models.PVZ, error) {
-	if !allowedCities[pvz.City] {
-		return nil, errors.New("city not allowed")
+// PVZPage is one page of the PVZ list with the metadata clients need to page
+// through it. NextCursor is set only for cursor pagination and is empty on the
+// last page.
+type PVZPage struct {
+	Items []PVZWithReceptions
+	// Stats holds per-PVZ aggregates keyed by PVZ id.
+	Stats      map[string]models.PVZStats
+	Total      int
+	NextCursor string
+}
+
+type PVZService struct {
+	pvz        repository.PVZRepository
+	receptions repository.ReceptionRepository
+	products   repository.ProductRepository
+}
+
+func NewPVZService(pvz repository.PVZRepository, receptions repository.ReceptionRepository, products repository.ProductRepository) *PVZService {
+	return &PVZService{pvz: pvz, receptions: receptions, products: products}
+}
+
+func (s *PVZService) CreatePVZ(ctx context.Context, pvz models.PVZ, actorID string) (*models.PVZ, error) {
+	if !slices.Contains(models.Cities, pvz.City) {
+		return nil, ErrCityNotAllowed
 	}
 
-	query := `
-		INSERT INTO pvz (id, registration_date, city)
-		VALUES ($1, $2, $3)
-		RETURNING id, registration_date, city
-	`
+	created, err := s.pvz.Create(ctx, pvz, actorID)
+	if errors.Is(err, repository.ErrPVZExists) {
+		return nil, ErrPVZExists
+	}
+	if err != nil {
+		return nil, err
+	}
 
-	row := db.QueryRow(query, pvz.ID, pvz.RegistrationDate, pvz.City)
+	metrics.PVZCreated.Inc()
+	return created, nil
+}
 
-	var newPVZ models.PVZ
-	if err := row.Scan(&newPVZ.ID, &newPVZ.RegistrationDate, &newPVZ.City); err != nil {
+func (s *PVZService) GetPVZList(ctx context.Context, filter PVZFilter, page, limit int) ([]PVZWithReceptions, error) {
+	offset := (page - 1) * limit
+	list, err := s.pvz.List(ctx, filter.toRepository(), limit, offset)
+	if err != nil {
 		return nil, err
 	}
 
-	return &newPVZ, nil
+	return s.withReceptions(ctx, list, filter.toRepository())
 }
 
-func GetPVZList(db *sql.DB, startDate, endDate *time.Time, page, limit int) ([]PVZWithReceptions, error) {
+// GetPVZPageByNumber is GetPVZList with the total count and per-PVZ stats.
+func (s *PVZService) GetPVZPageByNumber(ctx context.Context, filter PVZFilter, page, limit int) (*PVZPage, error) {
 	offset := (page - 1) * limit
-	query := `
-		SELECT id, registration_date, city
-		FROM pvz
-		WHERE ($1::timestamptz IS NULL OR registration_date >= $1::timestamptz)
-		  AND ($2::timestamptz IS NULL OR registration_date <= $2::timestamptz)
-		ORDER BY registration_date DESC
-		LIMIT $3 OFFSET $4
-	`
-
-	rows, err := db.Query(query, startDate, endDate, limit, offset)
+	list, err := s.pvz.List(ctx, filter.toRepository(), limit, offset)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	var results []PVZWithReceptions
+	return s.newPage(ctx, list, filter.toRepository(), "")
+}
 
-	for rows.Next() {
-		var pvz models.PVZ
-		if err := rows.Scan(&pvz.ID, &pvz.RegistrationDate, &pvz.City); err != nil {
-			return nil, err
-		}
+// GetPVZPage returns the page of PVZs following cursor, or the first page when
+// cursor is empty. Unlike page numbers, cursors stay stable while PVZs are
+// being added.
+func (s *PVZService) GetPVZPage(ctx context.Context, filter PVZFilter, cursor string, limit int) (*PVZPage, error) {
+	list, next, err := s.listAfter(ctx, filter, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.newPage(ctx, list, filter.toRepository(), next)
+}
+
+// ListPVZs returns the page following cursor with its receptions and products,
+// and the cursor of the next page. Unlike GetPVZPage it skips the totals and
+// stats, and costs three queries.
+func (s *PVZService) ListPVZs(ctx context.Context, filter PVZFilter, cursor string, limit int) ([]PVZWithReceptions, string, error) {
+	list, next, err := s.listAfter(ctx, filter, cursor, limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	items, err := s.withReceptions(ctx, list, filter.toRepository())
+	if err != nil {
+		return nil, "", err
+	}
+
+	return items, next, nil
+}
 
-		recs, err := getReceptionsWithProducts(db, pvz.ID, startDate, endDate)
+// listAfter returns the PVZs following cursor and the cursor of the next page.
+func (s *PVZService) listAfter(ctx context.Context, filter PVZFilter, cursor string, limit int) ([]models.PVZ, string, error) {
+	var after *repository.PVZCursor
+	if cursor != "" {
+		c, err := decodePVZCursor(cursor)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
+		after = c
+	}
 
-		results = append(results, PVZWithReceptions{
-			PVZ:        pvz,
-			Receptions: recs,
-		})
+	// One extra row tells whether there is a next page.
+	list, err := s.pvz.ListAfter(ctx, filter.toRepository(), after, limit+1)
+	if err != nil {
+		return nil, "", err
 	}
 
-	return results, nil
+	var next string
+	if len(list) > limit {
+		list = list[:limit]
+		last := list[len(list)-1]
+		next = encodePVZCursor(repository.PVZCursor{RegistrationDate: last.RegistrationDate, ID: last.ID})
+	}
+
+	return list, next, nil
 }
 
-func getReceptionsWithProducts(db *sql.DB, pvzID string, startDate, endDate *time.Time) ([]ReceptionWithProducts, error) {
-	query := `
-        SELECT id, date_time, status
-        FROM receptions
-        WHERE pvz_id = $1
-          AND ($2::timestamptz IS NULL OR date_time >= $2::timestamptz)
-          AND ($3::timestamptz IS NULL OR date_time <= $3::timestamptz)
-        ORDER BY date_time
-    `
-	rows, err := db.Query(query, pvzID, startDate, endDate)
+func (s *PVZService) newPage(ctx context.Context, list []models.PVZ, filter repository.PVZFilter, next string) (*PVZPage, error) {
+	items, err := s.withReceptions(ctx, list, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := s.pvz.Count(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
-	defer func(rows *sql.Rows) {
-		err := rows.Close()
+
+	stats := map[string]models.PVZStats{}
+	if len(list) > 0 {
+		pvzIDs := make([]string, len(list))
+		for i, pvz := range list {
+			pvzIDs[i] = pvz.ID
+		}
+
+		stats, err = s.pvz.Stats(ctx, pvzIDs, filter)
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
-	}(rows)
+	}
 
-	var receptions []ReceptionWithProducts
+	return &PVZPage{Items: items, Stats: stats, Total: total, NextCursor: next}, nil
+}
 
-	for rows.Next() {
-		var r models.Reception
-		if err := rows.Scan(&r.ID, &r.DateTime, &r.Status); err != nil {
-			return nil, err
+// withReceptions attaches receptions and their products to the PVZs. It costs
+// two queries however many PVZs and receptions there are.
+func (s *PVZService) withReceptions(ctx context.Context, list []models.PVZ, filter repository.PVZFilter) ([]PVZWithReceptions, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	pvzIDs := make([]string, len(list))
+	for i, pvz := range list {
+		pvzIDs[i] = pvz.ID
+	}
+
+	receptions, err := s.receptions.ListByPVZs(ctx, pvzIDs, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var products []models.Product
+	if len(receptions) > 0 {
+		receptionIDs := make([]string, len(receptions))
+		for i, r := range receptions {
+			receptionIDs[i] = r.ID
 		}
-		r.PVZID = pvzID
 
-		products, err := getProductsByReception(db, r.ID)
+		products, err = s.products.ListByReceptions(ctx, receptionIDs, filter.ProductType, filter.IncludeDeleted)
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		receptions = append(receptions, ReceptionWithProducts{
+	productsByReception := map[string][]models.Product{}
+	for _, p := range products {
+		productsByReception[p.ReceptionID] = append(productsByReception[p.ReceptionID], p)
+	}
+
+	receptionsByPVZ := map[string][]ReceptionWithProducts{}
+	for _, r := range receptions {
+		receptionsByPVZ[r.PVZID] = append(receptionsByPVZ[r.PVZID], ReceptionWithProducts{
 			Reception: r,
-			Products:  products,
+			Products:  productsByReception[r.ID],
 		})
 	}
 
-	return receptions, nil
+	results := make([]PVZWithReceptions, len(list))
+	for i, pvz := range list {
+		results[i] = PVZWithReceptions{
+			PVZ:        pvz,
+			Receptions: receptionsByPVZ[pvz.ID],
+		}
+	}
+
+	return results, nil
+}
+
+type pvzCursor struct {
+	RegistrationDate time.Time `json:"d"`
+	ID               string    `json:"i"`
+}
+
+// Cursors are opaque to clients; the encoding may change between releases.
+func encodePVZCursor(c repository.PVZCursor) string {
+	data, _ := json.Marshal(pvzCursor{RegistrationDate: c.RegistrationDate, ID: c.ID})
+	return base64.RawURLEncoding.EncodeToString(data)
 }
 
-func getProductsByReception(db *sql.DB, receptionID string) ([]models.Product, error) {
-	rows, err := db.Query(`
-        SELECT id, date_time, type
-        FROM products
-        WHERE reception_id = $1
-        ORDER BY date_time
-    `, receptionID)
+func decodePVZCursor(s string) (*repository.PVZCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
 	if err != nil {
-		return nil, err
+		return nil, ErrInvalidCursor
 	}
-	defer func(rows *sql.Rows) {
-		err := rows.Close()
-		if err != nil {
-			log.Fatal(err)
-		}
-	}(rows)
 
-	var products []models.Product
-	for rows.Next() {
-		var p models.Product
-		if err := rows.Scan(&p.ID, &p.DateTime, &p.Type); err != nil {
-			return nil, err
-		}
-		p.ReceptionID = receptionID
-		products = append(products, p)
+	var c pvzCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.RegistrationDate.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+	if _, err := uuid.Parse(c.ID); err != nil {
+		return nil, ErrInvalidCursor
 	}
 
-	return products, nil
+	return &repository.PVZCursor{RegistrationDate: c.RegistrationDate, ID: c.ID}, nil
 }
@@ -0,0 +1,161 @@
+package services_test
+
+import (
+	"avito-internship/internal/repository/postgres"
+	"avito-internship/internal/services"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	receptionsPerPVZ     = 3
+	productsPerReception = 5
+)
+
+// expectPVZListQueries registers the queries a listing of pageSize PVZs is
+// allowed to make. sqlmock fails on any query beyond these, so a passing call
+// proves the round-trip count.
+func expectPVZListQueries(mock sqlmock.Sqlmock, pageSize int) int {
+	now := time.Now()
+
+	pvzRows := sqlmock.NewRows([]string{"id", "registration_date", "city"})
+	receptionRows := sqlmock.NewRows([]string{"id", "pvz_id", "date_time", "status", "created_by", "closed_by", "closed_at"})
+	productRows := sqlmock.NewRows([]string{"id", "reception_id", "date_time", "type", "created_by", "deleted_by", "deleted_at"})
+
+	for i := 0; i < pageSize; i++ {
+		pvzID := fmt.Sprintf("pvz-%d", i)
+		pvzRows.AddRow(pvzID, now, "Москва")
+
+		for j := 0; j < receptionsPerPVZ; j++ {
+			receptionID := fmt.Sprintf("%s-rec-%d", pvzID, j)
+			receptionRows.AddRow(receptionID, pvzID, now, "close", nil, nil, nil)
+
+			for k := 0; k < productsPerReception; k++ {
+				productRows.AddRow(fmt.Sprintf("%s-prod-%d", receptionID, k), receptionID, now, "обувь", nil, nil, nil)
+			}
+		}
+	}
+
+	mock.ExpectQuery(`FROM pvz`).WillReturnRows(pvzRows)
+	mock.ExpectQuery(`FROM receptions`).WillReturnRows(receptionRows)
+	mock.ExpectQuery(`FROM products`).WillReturnRows(productRows)
+
+	return 3
+}
+
+// expectPVZPageQueries extends expectPVZListQueries with the total count and
+// the aggregates of the envelope response.
+func expectPVZPageQueries(mock sqlmock.Sqlmock, pageSize int) int {
+	n := expectPVZListQueries(mock, pageSize)
+
+	statsRows := sqlmock.NewRows([]string{"pvz_id", "total", "type", "receptions", "products"})
+	for i := 0; i < pageSize; i++ {
+		pvzID := fmt.Sprintf("pvz-%d", i)
+		statsRows.AddRow(pvzID, true, nil, receptionsPerPVZ, receptionsPerPVZ*productsPerReception)
+		statsRows.AddRow(pvzID, false, "обувь", receptionsPerPVZ, receptionsPerPVZ*productsPerReception)
+	}
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM pvz`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(100))
+	mock.ExpectQuery(`GROUPING SETS`).WillReturnRows(statsRows)
+
+	return n + 2
+}
+
+func newSQLPVZService(t testing.TB) (*services.PVZService, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return services.NewPVZService(
+		postgres.NewPVZRepository(db, time.Second),
+		postgres.NewReceptionRepository(db, time.Second),
+		postgres.NewProductRepository(db, time.Second),
+	), mock
+}
+
+func TestGetPVZList_ConstantRoundTrips(t *testing.T) {
+	for _, pageSize := range []int{1, 10, 30} {
+		t.Run(fmt.Sprintf("page=%d", pageSize), func(t *testing.T) {
+			svc, mock := newSQLPVZService(t)
+			expectPVZListQueries(mock, pageSize)
+
+			list, err := svc.GetPVZList(ctx, services.PVZFilter{}, 1, pageSize)
+			require.NoError(t, err)
+			require.Len(t, list, pageSize)
+			require.Len(t, list[pageSize-1].Receptions, receptionsPerPVZ)
+			require.Len(t, list[pageSize-1].Receptions[0].Products, productsPerReception)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetPVZPageByNumber_ConstantRoundTrips(t *testing.T) {
+	for _, pageSize := range []int{1, 10, 30} {
+		t.Run(fmt.Sprintf("page=%d", pageSize), func(t *testing.T) {
+			svc, mock := newSQLPVZService(t)
+			expectPVZPageQueries(mock, pageSize)
+
+			page, err := svc.GetPVZPageByNumber(ctx, services.PVZFilter{}, 1, pageSize)
+			require.NoError(t, err)
+			require.Len(t, page.Items, pageSize)
+			require.Equal(t, 100, page.Total)
+			require.Equal(t, receptionsPerPVZ, page.Stats["pvz-0"].ReceptionCount)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func BenchmarkGetPVZList(b *testing.B) {
+	for _, pageSize := range []int{1, 10, 30} {
+		b.Run(fmt.Sprintf("page=%d", pageSize), func(b *testing.B) {
+			svc, mock := newSQLPVZService(b)
+
+			queries := 0
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				queries += expectPVZListQueries(mock, pageSize)
+				b.StartTimer()
+
+				if _, err := svc.GetPVZList(ctx, services.PVZFilter{}, 1, pageSize); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			b.StopTimer()
+			if err := mock.ExpectationsWereMet(); err != nil {
+				b.Fatal(err)
+			}
+			b.ReportMetric(float64(queries)/float64(b.N), "queries/op")
+		})
+	}
+}
+
+func BenchmarkGetPVZPageByNumber(b *testing.B) {
+	for _, pageSize := range []int{1, 10, 30} {
+		b.Run(fmt.Sprintf("page=%d", pageSize), func(b *testing.B) {
+			svc, mock := newSQLPVZService(b)
+
+			queries := 0
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				queries += expectPVZPageQueries(mock, pageSize)
+				b.StartTimer()
+
+				if _, err := svc.GetPVZPageByNumber(ctx, services.PVZFilter{}, 1, pageSize); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			b.StopTimer()
+			if err := mock.ExpectationsWereMet(); err != nil {
+				b.Fatal(err)
+			}
+			b.ReportMetric(float64(queries)/float64(b.N), "queries/op")
+		})
+	}
+}
@@ -1,42 +1,46 @@
 package services_test
 
 import (
+	"avito-internship/internal/metrics"
 	"avito-internship/internal/models"
+	"avito-internship/internal/repository"
 	"avito-internship/internal/services"
 	"errors"
 	"testing"
 	"time"
 
-	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
+func newPVZService() (*services.PVZService, *mockPVZRepository, *mockReceptionRepository, *mockProductRepository) {
+	pvzRepo := new(mockPVZRepository)
+	receptionRepo := new(mockReceptionRepository)
+	productRepo := new(mockProductRepository)
+	return services.NewPVZService(pvzRepo, receptionRepo, productRepo), pvzRepo, receptionRepo, productRepo
+}
+
 func TestCreatePVZ_Success(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	assert.NoError(t, err)
-	defer db.Close()
+	svc, pvzRepo, _, _ := newPVZService()
 
 	pvz := models.PVZ{
 		ID:               "test-id-1",
 		RegistrationDate: time.Now(),
 		City:             "Москва",
 	}
+	pvzRepo.On("Create", pvz, "user-1").Return(&pvz, nil)
+	created := testutil.ToFloat64(metrics.PVZCreated)
 
-	mock.ExpectQuery(`INSERT INTO pvz`).
-		WithArgs(pvz.ID, pvz.RegistrationDate, pvz.City).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}).
-			AddRow(pvz.ID, pvz.RegistrationDate, pvz.City))
-
-	answer, err := services.CreatePVZ(db, pvz)
+	answer, err := svc.CreatePVZ(ctx, pvz, "user-1")
 	assert.NoError(t, err)
 	assert.Equal(t, pvz.ID, answer.ID)
-	assert.Equal(t, pvz.City, answer.City)
+	assert.Equal(t, created+1, testutil.ToFloat64(metrics.PVZCreated))
+	pvzRepo.AssertExpectations(t)
 }
 
 func TestCreatePVZ_CityNotAllowed(t *testing.T) {
-	db, _, err := sqlmock.New()
-	assert.NoError(t, err)
-	defer db.Close()
+	svc, pvzRepo, _, _ := newPVZService()
 
 	pvz := models.PVZ{
 		ID:               "test-id-1",
@@ -44,84 +48,163 @@ func TestCreatePVZ_CityNotAllowed(t *testing.T) {
 		City:             "Ростов",
 	}
 
-	answer, err := services.CreatePVZ(db, pvz)
+	answer, err := svc.CreatePVZ(ctx, pvz, "user-1")
 	assert.Nil(t, answer)
-	assert.Error(t, err)
-	assert.Equal(t, "city not allowed", err.Error())
+	assert.EqualError(t, err, "City not allowed")
+	pvzRepo.AssertNotCalled(t, "Create")
 }
 
-func TestCreatePVZ_DBError(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	assert.NoError(t, err)
-	defer db.Close()
-
-	pvz := models.PVZ{
-		ID:               "test-id-1",
-		RegistrationDate: time.Now(),
-		City:             "Москва",
-	}
+func TestCreatePVZ_RepoError(t *testing.T) {
+	svc, pvzRepo, _, _ := newPVZService()
 
-	mock.ExpectQuery(`INSERT INTO pvz`).
-		WithArgs(pvz.ID, pvz.RegistrationDate, pvz.City).
-		WillReturnError(errors.New("insert failed"))
+	pvz := models.PVZ{ID: "test-id-1", City: "Казань"}
+	pvzRepo.On("Create", pvz, "user-1").Return(nil, errors.New("insert failed"))
 
-	answer, err := services.CreatePVZ(db, pvz)
+	answer, err := svc.CreatePVZ(ctx, pvz, "user-1")
 	assert.Nil(t, answer)
-	assert.Error(t, err)
-	assert.Equal(t, "insert failed", err.Error())
+	assert.EqualError(t, err, "insert failed")
 }
 
 func TestGetPVZList_Success(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	assert.NoError(t, err)
-	defer db.Close()
+	svc, pvzRepo, receptionRepo, productRepo := newPVZService()
 
 	startDate := time.Now().Add(-24 * time.Hour)
 	endDate := time.Now()
-	page := 1
-	limit := 5
-	offset := 0
-
-	mock.ExpectQuery(`SELECT id, registration_date, city FROM pvz`).
-		WithArgs(startDate, endDate, limit, offset).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}).
-			AddRow("pvz-id", time.Now(), "Москва"))
-
-	mock.ExpectQuery(`SELECT id, date_time, status FROM receptions`).
-		WithArgs("pvz-id", startDate, endDate).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "status"}).
-			AddRow("rec-id", time.Now(), "in_progress"))
-
-	mock.ExpectQuery(`SELECT id, date_time, type FROM products`).
-		WithArgs("rec-id").
-		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "type"}).
-			AddRow("prod-id", time.Now(), "электроника"))
-
-	answer, err := services.GetPVZList(db, &startDate, &endDate, page, limit)
+	filter := services.PVZFilter{StartDate: &startDate, EndDate: &endDate, Cities: []string{"Москва", "Казань"}, ProductType: "обувь"}
+	repoFilter := repository.PVZFilter{StartDate: &startDate, EndDate: &endDate, Cities: []string{"Москва", "Казань"}, ProductType: "обувь"}
+
+	pvzRepo.On("List", repoFilter, 5, 5).
+		Return([]models.PVZ{{ID: "pvz-1", City: "Москва"}, {ID: "pvz-2", City: "Казань"}}, nil)
+	receptionRepo.On("ListByPVZs", []string{"pvz-1", "pvz-2"}, repoFilter).
+		Return([]models.Reception{{ID: "rec-1", PVZID: "pvz-1"}}, nil)
+	productRepo.On("ListByReceptions", []string{"rec-1"}, "обувь", false).
+		Return([]models.Product{{ID: "prod-1", ReceptionID: "rec-1"}}, nil)
+
+	answer, err := svc.GetPVZList(ctx, filter, 2, 5)
 	assert.NoError(t, err)
-	assert.Len(t, answer, 1)
+	assert.Len(t, answer, 2)
 	assert.Equal(t, "Москва", answer[0].PVZ.City)
 	assert.Len(t, answer[0].Receptions, 1)
-	assert.Len(t, answer[0].Receptions[0].Products, 1)
+	assert.Equal(t, "prod-1", answer[0].Receptions[0].Products[0].ID)
+	assert.Empty(t, answer[1].Receptions)
+	pvzRepo.AssertExpectations(t)
+	receptionRepo.AssertExpectations(t)
+	productRepo.AssertExpectations(t)
+}
+
+func TestGetPVZList_RepoError(t *testing.T) {
+	svc, pvzRepo, receptionRepo, _ := newPVZService()
+
+	pvzRepo.On("List", repository.PVZFilter{}, 10, 0).
+		Return([]models.PVZ{{ID: "pvz-1"}}, nil)
+	receptionRepo.On("ListByPVZs", []string{"pvz-1"}, repository.PVZFilter{}).
+		Return(nil, errors.New("query error"))
+
+	answer, err := svc.GetPVZList(ctx, services.PVZFilter{}, 1, 10)
+	assert.Nil(t, answer)
+	assert.EqualError(t, err, "query error")
 }
 
-func TestGetPVZList_DBError(t *testing.T) {
-	db, mock, err := sqlmock.New()
+func TestGetPVZPage_FollowsCursor(t *testing.T) {
+	svc, pvzRepo, receptionRepo, _ := newPVZService()
+
+	newest := time.Date(2025, 4, 24, 12, 0, 0, 0, time.UTC)
+	first := []models.PVZ{
+		{ID: "33333333-3333-3333-3333-333333333333", RegistrationDate: newest},
+		{ID: "22222222-2222-2222-2222-222222222222", RegistrationDate: newest.Add(-time.Hour)},
+		{ID: "11111111-1111-1111-1111-111111111111", RegistrationDate: newest.Add(-2 * time.Hour)},
+	}
+
+	pvzRepo.On("ListAfter", repository.PVZFilter{}, (*repository.PVZCursor)(nil), 3).
+		Return(first, nil)
+	receptionRepo.On("ListByPVZs", mock.Anything, repository.PVZFilter{}).Return(nil, nil)
+	pvzRepo.On("Count", repository.PVZFilter{}).Return(3, nil)
+	pvzRepo.On("Stats", mock.Anything, repository.PVZFilter{}).Return(map[string]models.PVZStats{}, nil)
+
+	page, err := svc.GetPVZPage(ctx, services.PVZFilter{}, "", 2)
 	assert.NoError(t, err)
-	defer db.Close()
+	assert.Len(t, page.Items, 2)
+	assert.NotEmpty(t, page.NextCursor)
 
-	startDate := time.Now().Add(-24 * time.Hour)
-	endDate := time.Now()
-	page := 1
-	limit := 5
-	offset := 0
+	pvzRepo.On("ListAfter", repository.PVZFilter{}, &repository.PVZCursor{
+		RegistrationDate: first[1].RegistrationDate,
+		ID:               first[1].ID,
+	}, 3).Return(first[2:], nil)
+
+	page, err = svc.GetPVZPage(ctx, services.PVZFilter{}, page.NextCursor, 2)
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+	assert.Equal(t, first[2].ID, page.Items[0].PVZ.ID)
+	assert.Empty(t, page.NextCursor)
+	assert.Equal(t, 3, page.Total)
+	pvzRepo.AssertExpectations(t)
+}
 
-	mock.ExpectQuery(`SELECT id, registration_date, city FROM pvz`).
-		WithArgs(startDate, endDate, limit, offset).
-		WillReturnError(errors.New("query error"))
+func TestGetPVZPage_InvalidCursor(t *testing.T) {
+	svc, pvzRepo, _, _ := newPVZService()
 
-	answer, err := services.GetPVZList(db, &startDate, &endDate, page, limit)
-	assert.Nil(t, answer)
-	assert.Error(t, err)
-	assert.Equal(t, "query error", err.Error())
+	for _, cursor := range []string{"not base64!", "e30", "eyJkIjoiMjAyNS0wNC0yNFQxMjowMDowMFoiLCJpIjoieCJ9"} {
+		page, err := svc.GetPVZPage(ctx, services.PVZFilter{}, cursor, 10)
+		assert.Nil(t, page)
+		assert.ErrorIs(t, err, services.ErrInvalidCursor)
+	}
+	pvzRepo.AssertNotCalled(t, "ListAfter")
+}
+
+func TestListPVZs_WithoutTotalsOrStats(t *testing.T) {
+	svc, pvzRepo, receptionRepo, productRepo := newPVZService()
+
+	newest := time.Date(2025, 4, 24, 12, 0, 0, 0, time.UTC)
+	pvzRepo.On("ListAfter", repository.PVZFilter{}, (*repository.PVZCursor)(nil), 2).Return([]models.PVZ{
+		{ID: "22222222-2222-2222-2222-222222222222", RegistrationDate: newest},
+		{ID: "11111111-1111-1111-1111-111111111111", RegistrationDate: newest.Add(-time.Hour)},
+	}, nil)
+	receptionRepo.On("ListByPVZs", []string{"22222222-2222-2222-2222-222222222222"}, repository.PVZFilter{}).
+		Return([]models.Reception{{ID: "rec-1", PVZID: "22222222-2222-2222-2222-222222222222"}}, nil)
+	productRepo.On("ListByReceptions", []string{"rec-1"}, "", false).
+		Return([]models.Product{{ID: "prod-1", ReceptionID: "rec-1"}}, nil)
+
+	list, next, err := svc.ListPVZs(ctx, services.PVZFilter{}, "", 1)
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Len(t, list[0].Receptions, 1)
+	assert.Len(t, list[0].Receptions[0].Products, 1)
+	assert.NotEmpty(t, next)
+	pvzRepo.AssertNotCalled(t, "Count")
+	pvzRepo.AssertNotCalled(t, "Stats")
+}
+
+func TestGetPVZPageByNumber_TotalsAndStats(t *testing.T) {
+	svc, pvzRepo, receptionRepo, productRepo := newPVZService()
+
+	stats := map[string]models.PVZStats{
+		"pvz-1": {ReceptionCount: 1, ProductCounts: map[string]int{"обувь": 2}},
+	}
+
+	pvzRepo.On("List", repository.PVZFilter{}, 2, 2).
+		Return([]models.PVZ{{ID: "pvz-1"}, {ID: "pvz-2"}}, nil)
+	pvzRepo.On("Count", repository.PVZFilter{}).Return(5, nil)
+	pvzRepo.On("Stats", []string{"pvz-1", "pvz-2"}, repository.PVZFilter{}).Return(stats, nil)
+	receptionRepo.On("ListByPVZs", []string{"pvz-1", "pvz-2"}, repository.PVZFilter{}).
+		Return([]models.Reception{{ID: "rec-1", PVZID: "pvz-1"}}, nil)
+	productRepo.On("ListByReceptions", []string{"rec-1"}, "", false).Return(nil, nil)
+
+	page, err := svc.GetPVZPageByNumber(ctx, services.PVZFilter{}, 2, 2)
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+	assert.Equal(t, 5, page.Total)
+	assert.Equal(t, 2, page.Stats["pvz-1"].ProductCounts["обувь"])
+	pvzRepo.AssertExpectations(t)
+}
+
+func TestGetPVZPageByNumber_CountError(t *testing.T) {
+	svc, pvzRepo, _, _ := newPVZService()
+
+	pvzRepo.On("List", repository.PVZFilter{}, 10, 0).Return(nil, nil)
+	pvzRepo.On("Count", repository.PVZFilter{}).Return(0, errors.New("count failed"))
+
+	page, err := svc.GetPVZPageByNumber(ctx, services.PVZFilter{}, 1, 10)
+	assert.Nil(t, page)
+	assert.EqualError(t, err, "count failed")
+	pvzRepo.AssertNotCalled(t, "Stats")
 }
@@ -1,54 +1,46 @@
 package services
 
 import (
+	"avito-internship/internal/metrics"
 	"avito-internship/internal/models"
-	"database/sql"
+	"avito-internship/internal/repository"
+	"context"
 	"errors"
 )
 
-func CreateReception(db *sql.DB, pvzID string) (*models.Reception, error) {
-	var existing string
-	err := db.QueryRow(`
-        SELECT id FROM receptions
-        WHERE pvz_id = $1 AND status = 'in_progress'
-        LIMIT 1
-    `, pvzID).Scan(&existing)
-	if err == nil {
-		return nil, errors.New("already an open reception")
-	} else if err != sql.ErrNoRows {
-		return nil, err
-	}
+type ReceptionService struct {
+	receptions repository.ReceptionRepository
+}
 
-	var reception models.Reception
-	row := db.QueryRow(`
-        INSERT INTO receptions (pvz_id, status)
-        VALUES ($1, 'in_progress')
-        RETURNING id, date_time, status
-    `, pvzID)
-	if err := row.Scan(&reception.ID, &reception.DateTime, &reception.Status); err != nil {
+func NewReceptionService(receptions repository.ReceptionRepository) *ReceptionService {
+	return &ReceptionService{receptions: receptions}
+}
+
+func (s *ReceptionService) CreateReception(ctx context.Context, pvzID, actorID string) (*models.Reception, error) {
+	reception, err := s.receptions.Create(ctx, pvzID, actorID)
+	if errors.Is(err, repository.ErrReceptionAlreadyOpen) {
+		return nil, ErrReceptionAlreadyOpen
+	}
+	if errors.Is(err, repository.ErrPVZNotFound) {
+		return nil, ErrPVZNotFound
+	}
+	if err != nil {
 		return nil, err
 	}
-	reception.PVZID = pvzID
-	return &reception, nil
+
+	metrics.ReceptionsCreated.Inc()
+	return reception, nil
 }
 
-func CloseLastReception(db *sql.DB, pvzID string) error {
-	res, err := db.Exec(`
-        UPDATE receptions
-        SET status = 'close'
-        WHERE id = (
-            SELECT id FROM receptions
-            WHERE pvz_id = $1 AND status = 'in_progress'
-            ORDER BY date_time DESC
-            LIMIT 1
-        )
-    `, pvzID)
+func (s *ReceptionService) CloseLastReception(ctx context.Context, pvzID, actorID string) error {
+	_, err := s.receptions.CloseLast(ctx, pvzID, actorID)
+	if errors.Is(err, repository.ErrNoOpenReception) {
+		return ErrNoActiveReception
+	}
 	if err != nil {
 		return err
 	}
-	rows, _ := res.RowsAffected()
-	if rows == 0 {
-		return errors.New("no active reception")
-	}
+
+	metrics.ReceptionsClosed.Inc()
 	return nil
 }
@@ -1,113 +1,68 @@
 package services_test
 
 import (
+	"avito-internship/internal/metrics"
+	"avito-internship/internal/models"
+	"avito-internship/internal/repository"
 	"avito-internship/internal/services"
-	"database/sql"
 	"errors"
 	"testing"
-	"time"
 
-	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestCreateReception_Success(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	assert.NoError(t, err)
-	defer db.Close()
-
-	pvzID := "pvz-123"
-
-	mock.ExpectQuery(`SELECT id FROM receptions`).
-		WithArgs(pvzID).
-		WillReturnError(sql.ErrNoRows)
+	repo := new(mockReceptionRepository)
+	repo.On("Create", "pvz-123", "user-1").
+		Return(&models.Reception{ID: "rec-id", PVZID: "pvz-123", Status: "in_progress"}, nil)
 
-	now := time.Now()
-	mock.ExpectQuery(`INSERT INTO receptions`).
-		WithArgs(pvzID).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "status"}).
-			AddRow("rec-id", now, "in_progress"))
+	created := testutil.ToFloat64(metrics.ReceptionsCreated)
 
-	r, err := services.CreateReception(db, pvzID)
+	r, err := services.NewReceptionService(repo).CreateReception(ctx, "pvz-123", "user-1")
 	assert.NoError(t, err)
-	assert.Equal(t, "in_progress", r.Status)
-	assert.Equal(t, pvzID, r.PVZID)
 	assert.Equal(t, "rec-id", r.ID)
+	assert.Equal(t, created+1, testutil.ToFloat64(metrics.ReceptionsCreated))
+	repo.AssertExpectations(t)
 }
 
 func TestCreateReception_AlreadyExists(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	assert.NoError(t, err)
-	defer db.Close()
-
-	pvzID := "pvz-123"
+	repo := new(mockReceptionRepository)
+	repo.On("Create", "pvz-123", "user-1").Return(nil, repository.ErrReceptionAlreadyOpen)
 
-	mock.ExpectQuery(`SELECT id FROM receptions`).
-		WithArgs(pvzID).
-		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("rec-1"))
+	created := testutil.ToFloat64(metrics.ReceptionsCreated)
 
-	r, err := services.CreateReception(db, pvzID)
+	r, err := services.NewReceptionService(repo).CreateReception(ctx, "pvz-123", "user-1")
 	assert.Nil(t, r)
-	assert.EqualError(t, err, "already an open reception")
+	assert.EqualError(t, err, "There is already an open reception")
+	assert.Equal(t, created, testutil.ToFloat64(metrics.ReceptionsCreated))
 }
 
-func TestCreateReception_DBError(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	assert.NoError(t, err)
-	defer db.Close()
-
-	pvzID := "pvz-123"
-
-	mock.ExpectQuery(`SELECT id FROM receptions`).
-		WithArgs(pvzID).
-		WillReturnError(errors.New("db failure"))
+func TestCreateReception_RepoError(t *testing.T) {
+	repo := new(mockReceptionRepository)
+	repo.On("Create", "pvz-123", "user-1").Return(nil, errors.New("db failure"))
 
-	r, err := services.CreateReception(db, pvzID)
+	r, err := services.NewReceptionService(repo).CreateReception(ctx, "pvz-123", "user-1")
 	assert.Nil(t, r)
 	assert.EqualError(t, err, "db failure")
 }
 
 func TestCloseLastReception_Success(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	assert.NoError(t, err)
-	defer db.Close()
-
-	pvzID := "pvz-123"
+	repo := new(mockReceptionRepository)
+	repo.On("CloseLast", "pvz-123", "user-1").Return(&models.Reception{ID: "rec-id", Status: "close"}, nil)
 
-	mock.ExpectExec(`UPDATE receptions`).
-		WithArgs(pvzID).
-		WillReturnResult(sqlmock.NewResult(1, 1)) // 1 row affected
+	closed := testutil.ToFloat64(metrics.ReceptionsClosed)
 
-	err = services.CloseLastReception(db, pvzID)
+	err := services.NewReceptionService(repo).CloseLastReception(ctx, "pvz-123", "user-1")
 	assert.NoError(t, err)
+	assert.Equal(t, closed+1, testutil.ToFloat64(metrics.ReceptionsClosed))
+	repo.AssertExpectations(t)
 }
 
 func TestCloseLastReception_NoActive(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	assert.NoError(t, err)
-	defer db.Close()
-
-	pvzID := "pvz-123"
-
-	mock.ExpectExec(`UPDATE receptions`).
-		WithArgs(pvzID).
-		WillReturnResult(sqlmock.NewResult(1, 0)) // 0 rows affected
-
-	err = services.CloseLastReception(db, pvzID)
-	assert.EqualError(t, err, "no active reception")
-}
-
-func TestCloseLastReception_DBError(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	assert.NoError(t, err)
-	defer db.Close()
-
-	pvzID := "pvz-123"
-
-	mock.ExpectExec(`UPDATE receptions`).
-		WithArgs(pvzID).
-		WillReturnError(errors.New("update failed"))
+	repo := new(mockReceptionRepository)
+	repo.On("CloseLast", "pvz-123", "user-1").Return(nil, repository.ErrNoOpenReception)
 
-	err = services.CloseLastReception(db, pvzID)
-	assert.EqualError(t, err, "update failed")
+	err := services.NewReceptionService(repo).CloseLastReception(ctx, "pvz-123", "user-1")
+	assert.EqualError(t, err, "No active reception")
 }
@@ -0,0 +1,18 @@
+package services
+
+// Services are the business services the HTTP and gRPC transports delegate to.
+type Services struct {
+	Users       *UserService
+	Sessions    *SessionService
+	Assignments *AssignmentService
+	PVZ         *PVZService
+	Receptions  *ReceptionService
+	Products    *ProductService
+	Audit       *AuditService
+}
+
+// PageLimits are the default and the maximum page size of the PVZ list.
+type PageLimits struct {
+	Default int
+	Max     int
+}
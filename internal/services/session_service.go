@@ -0,0 +1,69 @@
+package services
+
+import (
+	"avito-internship/internal/auth"
+	"avito-internship/internal/models"
+	"avito-internship/internal/repository"
+	"context"
+	"errors"
+	"time"
+)
+
+type SessionService struct {
+	sessions repository.SessionRepository
+}
+
+func NewSessionService(sessions repository.SessionRepository) *SessionService {
+	return &SessionService{sessions: sessions}
+}
+
+// CreateSession starts a server-side session for a logged in user and returns
+// the refresh token that can later be exchanged for new access tokens.
+func (s *SessionService) CreateSession(ctx context.Context, user *models.User, ttl time.Duration) (*models.Session, string, error) {
+	refreshToken, hash, err := auth.NewRefreshToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	session, err := s.sessions.Create(ctx, models.Session{
+		UserID:    user.ID,
+		Role:      user.Role,
+		ExpiresAt: time.Now().Add(ttl),
+	}, hash)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return session, refreshToken, nil
+}
+
+// RefreshSession rotates the refresh token of a live session. The old token
+// stops working as soon as the new one is issued.
+func (s *SessionService) RefreshSession(ctx context.Context, refreshToken string, ttl time.Duration) (*models.Session, string, error) {
+	newToken, newHash, err := auth.NewRefreshToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	session, err := s.sessions.Rotate(ctx, auth.HashRefreshToken(refreshToken), newHash, time.Now().Add(ttl))
+	if errors.Is(err, repository.ErrSessionNotFound) {
+		return nil, "", ErrInvalidRefreshToken
+	} else if err != nil {
+		return nil, "", err
+	}
+
+	return session, newToken, nil
+}
+
+func (s *SessionService) RevokeSession(ctx context.Context, sessionID string) error {
+	return s.sessions.Revoke(ctx, sessionID)
+}
+
+// RevokeUserSessions logs a user out everywhere, e.g. when a terminal is lost.
+func (s *SessionService) RevokeUserSessions(ctx context.Context, userID string) (int64, error) {
+	return s.sessions.RevokeByUser(ctx, userID)
+}
+
+func (s *SessionService) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	return s.sessions.IsActive(ctx, sessionID)
+}
@@ -0,0 +1,95 @@
+package services_test
+
+import (
+	"avito-internship/internal/auth"
+	"avito-internship/internal/models"
+	"avito-internship/internal/repository/postgres"
+	"avito-internship/internal/services"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSessionService(db *sql.DB) *services.SessionService {
+	return services.NewSessionService(postgres.NewSessionRepository(db, time.Second))
+}
+
+func TestCreateSession_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	user := &models.User{ID: "user-id", Role: "employee"}
+
+	mock.ExpectQuery(`INSERT INTO sessions`).
+		WithArgs(user.ID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("session-id"))
+
+	session, refreshToken, err := newSessionService(db).CreateSession(ctx, user, time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, "session-id", session.ID)
+	assert.Equal(t, "employee", session.Role)
+	assert.NotEmpty(t, refreshToken)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshSession_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`UPDATE sessions s`).
+		WithArgs(auth.HashRefreshToken("old-token"), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "role"}).AddRow("session-id", "user-id", "moderator"))
+
+	session, refreshToken, err := newSessionService(db).RefreshSession(ctx, "old-token", time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, "session-id", session.ID)
+	assert.Equal(t, "user-id", session.UserID)
+	assert.Equal(t, "moderator", session.Role)
+	assert.NotEqual(t, "old-token", refreshToken)
+}
+
+func TestRefreshSession_Revoked(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`UPDATE sessions s`).
+		WithArgs(auth.HashRefreshToken("old-token"), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnError(sql.ErrNoRows)
+
+	session, _, err := newSessionService(db).RefreshSession(ctx, "old-token", time.Hour)
+	assert.Nil(t, session)
+	assert.ErrorIs(t, err, services.ErrInvalidRefreshToken)
+}
+
+func TestRevokeSession(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(`UPDATE sessions`).
+		WithArgs("session-id").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, newSessionService(db).RevokeSession(ctx, "session-id"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIsSessionActive_Unknown(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`FROM sessions`).
+		WithArgs("session-id").
+		WillReturnError(sql.ErrNoRows)
+
+	active, err := newSessionService(db).IsSessionActive(ctx, "session-id")
+	assert.NoError(t, err)
+	assert.False(t, active)
+}
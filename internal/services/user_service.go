@@ -0,0 +1,82 @@
+package services
+
+import (
+	"avito-internship/internal/auth"
+	"avito-internship/internal/models"
+	"avito-internship/internal/repository"
+	"context"
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// maxPasswordBytes is the longest password bcrypt hashes.
+const maxPasswordBytes = 72
+
+type UserService struct {
+	users  repository.UserRepository
+	policy *auth.Policy
+}
+
+func NewUserService(users repository.UserRepository, policy *auth.Policy) *UserService {
+	return &UserService{users: users, policy: policy}
+}
+
+// Register signs up a new employee. Accounts with other roles are created
+// by an administrator, see CreateUser.
+func (s *UserService) Register(ctx context.Context, email, password string) (*models.User, error) {
+	return s.create(ctx, email, password, auth.RoleEmployee)
+}
+
+// CreateUser creates an account with any role the policy knows.
+func (s *UserService) CreateUser(ctx context.Context, email, password, role string) (*models.User, error) {
+	if !s.policy.HasRole(role) {
+		return nil, ErrRoleNotAllowed
+	}
+
+	return s.create(ctx, email, password, role)
+}
+
+func (s *UserService) create(ctx context.Context, email, password, role string) (*models.User, error) {
+	// bcrypt only accepts 72 bytes, which the request binding cannot check
+	// as it counts characters.
+	if len(password) > maxPasswordBytes {
+		return nil, ErrPasswordTooLong
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.users.Create(ctx, models.User{
+		Email:        normalizeEmail(email),
+		Role:         role,
+		PasswordHash: string(hash),
+	})
+	if errors.Is(err, repository.ErrUserExists) {
+		return nil, ErrUserExists
+	}
+
+	return user, err
+}
+
+func (s *UserService) Login(ctx context.Context, email, password string) (*models.User, error) {
+	user, err := s.users.GetByEmail(ctx, normalizeEmail(email))
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return nil, ErrInvalidCredentials
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	return user, nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,155 @@
+package services_test
+
+import (
+	"avito-internship/internal/auth"
+	"avito-internship/internal/repository/postgres"
+	"avito-internship/internal/services"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newUserService(db *sql.DB) *services.UserService {
+	return services.NewUserService(postgres.NewUserRepository(db, time.Second), auth.NewPolicy(auth.DefaultPolicyConfig))
+}
+
+func TestRegister_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`INSERT INTO users`).
+		WithArgs("user@example.com", sqlmock.AnyArg(), "employee").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("user-id"))
+
+	user, err := newUserService(db).Register(ctx, " User@Example.com ", "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, "user-id", user.ID)
+	assert.Equal(t, "user@example.com", user.Email)
+	assert.Equal(t, "employee", user.Role)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte("secret")))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateUser_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`INSERT INTO users`).
+		WithArgs("mod@example.com", sqlmock.AnyArg(), "moderator").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("user-id"))
+
+	user, err := newUserService(db).CreateUser(ctx, "mod@example.com", "secret", "moderator")
+	assert.NoError(t, err)
+	assert.Equal(t, "moderator", user.Role)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateUser_RoleNotAllowed(t *testing.T) {
+	db, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	user, err := newUserService(db).CreateUser(ctx, "user@example.com", "secret", "client")
+	assert.Nil(t, user)
+	assert.EqualError(t, err, "Role not allowed")
+}
+
+func TestRegister_PasswordTooLong(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	// 37 Cyrillic characters are 74 bytes.
+	user, err := newUserService(db).Register(ctx, "user@example.com", strings.Repeat("я", 37))
+	assert.Nil(t, user)
+	assert.ErrorIs(t, err, services.ErrPasswordTooLong)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRegister_AlreadyExists(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`INSERT INTO users`).
+		WithArgs("user@example.com", sqlmock.AnyArg(), "employee").
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	user, err := newUserService(db).Register(ctx, "user@example.com", "secret")
+	assert.Nil(t, user)
+	assert.EqualError(t, err, "User already exists")
+}
+
+func TestLogin_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	mock.ExpectQuery(`SELECT id, email, password_hash, role FROM users`).
+		WithArgs("user@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "password_hash", "role"}).
+			AddRow("user-id", "user@example.com", string(hash), "employee"))
+
+	user, err := newUserService(db).Login(ctx, "user@example.com", "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, "user-id", user.ID)
+	assert.Equal(t, "employee", user.Role)
+}
+
+func TestLogin_WrongPassword(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	mock.ExpectQuery(`SELECT id, email, password_hash, role FROM users`).
+		WithArgs("user@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "password_hash", "role"}).
+			AddRow("user-id", "user@example.com", string(hash), "employee"))
+
+	user, err := newUserService(db).Login(ctx, "user@example.com", "wrong")
+	assert.Nil(t, user)
+	assert.ErrorIs(t, err, services.ErrInvalidCredentials)
+}
+
+func TestLogin_UnknownUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT id, email, password_hash, role FROM users`).
+		WithArgs("ghost@example.com").
+		WillReturnError(sql.ErrNoRows)
+
+	user, err := newUserService(db).Login(ctx, "ghost@example.com", "secret")
+	assert.Nil(t, user)
+	assert.ErrorIs(t, err, services.ErrInvalidCredentials)
+}
+
+func TestLogin_DBError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT id, email, password_hash, role FROM users`).
+		WithArgs("user@example.com").
+		WillReturnError(errors.New("select failed"))
+
+	user, err := newUserService(db).Login(ctx, "user@example.com", "secret")
+	assert.Nil(t, user)
+	assert.EqualError(t, err, "select failed")
+}
@@ -1,23 +1,75 @@
 package tests
 
 import (
+	"avito-internship/internal/app"
+	"avito-internship/internal/auth"
+	"avito-internship/internal/config"
+	"avito-internship/internal/services"
+	"avito-internship/internal/transport"
 	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
+	"net/http/httptest"
+	"os"
 	"testing"
+	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
 
-const baseURL = "http://localhost:8080"
+// baseURL points at INTEGRATION_BASE_URL when set, e.g. a server started with
+// docker compose. Otherwise the tests run against an in-process server on the
+// memory backend.
+var baseURL = os.Getenv("INTEGRATION_BASE_URL")
+
+func TestMain(m *testing.M) {
+	var server *httptest.Server
+	if baseURL == "" {
+		var err error
+		if server, err = newMemoryServer(); err != nil {
+			log.Fatalf("Error starting test server: %s", err)
+		}
+		baseURL = server.URL
+	}
+
+	code := m.Run()
+	if server != nil {
+		server.Close()
+	}
+	os.Exit(code)
+}
+
+func newMemoryServer() (*httptest.Server, error) {
+	tokens, err := auth.NewTokenManager(map[string][]byte{"test": []byte("secret")}, "test", time.Hour, time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	svc, err := app.NewServices(config.StorageMemory, config.DatabaseConfig{}, auth.NewPolicy(auth.DefaultPolicyConfig), true)
+	if err != nil {
+		return nil, err
+	}
+
+	gin.SetMode(gin.TestMode)
+	cfg := config.Default()
+	router := transport.SetupRouter(tokens, auth.NewPolicy(auth.DefaultPolicyConfig), svc, transport.Options{
+		EnableDummyLogin: true,
+		PageLimits:       services.PageLimits{Default: cfg.Pagination.DefaultLimit, Max: cfg.Pagination.MaxLimit},
+		ReadinessTimeout: cfg.ReadinessTimeout,
+	})
+	return httptest.NewServer(router), nil
+}
 
 func TestIntegrationHTTPFlow(t *testing.T) {
 	token := getToken(t, "moderator")
 
 	pvzID := "11111111-1111-1111-1111-111111111111"
 	createPVZ(t, token, pvzID)
+	assignEmployee(t, token, pvzID, auth.DummyEmployeeID)
 
 	employeeToken := getToken(t, "employee")
 
@@ -52,6 +104,13 @@ func createPVZ(t *testing.T, token, pvzID string) {
 	postJSON(t, "/pvz", token, payload)
 }
 
+func assignEmployee(t *testing.T, token, pvzID, userID string) {
+	payload := map[string]string{
+		"userId": userID,
+	}
+	postJSON(t, "/pvz/"+pvzID+"/employees", token, payload)
+}
+
 func createReception(t *testing.T, token, pvzID string) {
 	payload := map[string]string{
 		"pvzId": pvzID,
@@ -0,0 +1,18 @@
+// Package errcode holds the codes of errors raised by the transports
+// themselves, shared by the HTTP handlers, the middleware and the gRPC API.
+// Domain errors carry their own code, see services.Error.
+package errcode
+
+const (
+	InvalidInput = "invalid_input"
+	Internal     = "internal_error"
+	PVZForbidden = "pvz_forbidden"
+	Timeout      = "timeout"
+	Canceled     = "request_canceled"
+
+	MissingToken   = "missing_token"
+	InvalidToken   = "invalid_token"
+	InvalidRole    = "invalid_role"
+	SessionRevoked = "session_revoked"
+	AccessDenied   = "access_denied"
+)
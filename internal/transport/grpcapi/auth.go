@@ -0,0 +1,85 @@
+package grpcapi
+
+import (
+	"avito-internship/internal/auth"
+	"avito-internship/internal/transport/errcode"
+	"avito-internship/internal/transport/grpcapi/pvz_v1"
+	"avito-internship/internal/transport/middleware"
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// methodActions lists the permission each method requires. Methods missing
+// here are rejected, so that a new method is never public by accident.
+var methodActions = map[string]auth.Action{
+	pvz_v1.PVZService_GetPVZList_FullMethodName:         auth.ActionListPVZ,
+	pvz_v1.PVZService_CreateReception_FullMethodName:    auth.ActionCreateReception,
+	pvz_v1.PVZService_CloseLastReception_FullMethodName: auth.ActionCloseReception,
+	pvz_v1.PVZService_AddProduct_FullMethodName:         auth.ActionAddProduct,
+	pvz_v1.PVZService_DeleteLastProduct_FullMethodName:  auth.ActionDeleteProduct,
+}
+
+type claimsKey struct{}
+
+// authInterceptor is the gRPC counterpart of middleware.AuthMiddleware
+// followed by middleware.RequirePermission.
+func authInterceptor(tokens *auth.TokenManager, policy *auth.Policy, sessions middleware.SessionChecker) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		action, ok := methodActions[info.FullMethod]
+		if !ok {
+			return nil, statusError(ctx, codes.PermissionDenied, errcode.AccessDenied)
+		}
+
+		token, ok := strings.CutPrefix(firstMetadata(ctx, "authorization"), "Bearer ")
+		if !ok {
+			return nil, statusError(ctx, codes.Unauthenticated, errcode.MissingToken)
+		}
+
+		claims, err := tokens.Parse(token)
+		if err != nil {
+			return nil, statusError(ctx, codes.Unauthenticated, errcode.InvalidToken)
+		}
+
+		if !policy.HasRole(claims.Role) {
+			return nil, statusError(ctx, codes.PermissionDenied, errcode.InvalidRole)
+		}
+
+		if claims.SessionID != "" {
+			active, err := sessions.IsSessionActive(ctx, claims.SessionID)
+			if err != nil {
+				return nil, toStatus(ctx, err)
+			}
+			if !active {
+				return nil, statusError(ctx, codes.Unauthenticated, errcode.SessionRevoked)
+			}
+		}
+
+		if !policy.Allows(claims.Role, action) {
+			return nil, statusError(ctx, codes.PermissionDenied, errcode.AccessDenied)
+		}
+
+		return handler(context.WithValue(ctx, claimsKey{}, claims), req)
+	}
+}
+
+// claimsFrom returns the claims authInterceptor stored for the call.
+func claimsFrom(ctx context.Context) *auth.Claims {
+	claims, _ := ctx.Value(claimsKey{}).(*auth.Claims)
+	return claims
+}
+
+func actorID(ctx context.Context) string {
+	return claimsFrom(ctx).UserID()
+}
+
+func firstMetadata(ctx context.Context, key string) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
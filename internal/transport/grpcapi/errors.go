@@ -0,0 +1,68 @@
+package grpcapi
+
+import (
+	"avito-internship/internal/i18n"
+	"avito-internship/internal/services"
+	"avito-internship/internal/transport/errcode"
+	"context"
+	"errors"
+	"log"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain is the ErrorInfo domain of every error detail.
+const errorDomain = "pvz.v1"
+
+// statusCodes maps domain errors to gRPC codes. Domain errors not listed here
+// are reported as FailedPrecondition.
+var statusCodes = map[*services.Error]codes.Code{
+	services.ErrInvalidCredentials:   codes.Unauthenticated,
+	services.ErrInvalidRefreshToken:  codes.Unauthenticated,
+	services.ErrInvalidCursor:        codes.InvalidArgument,
+	services.ErrPVZNotFound:          codes.NotFound,
+	services.ErrUserNotFound:         codes.NotFound,
+	services.ErrPVZExists:            codes.AlreadyExists,
+	services.ErrUserExists:           codes.AlreadyExists,
+	services.ErrReceptionAlreadyOpen: codes.AlreadyExists,
+}
+
+// toStatus converts err to a gRPC status error. Queries that ran out of time
+// or were canceled with the call are reported as DeadlineExceeded and
+// Canceled. Other errors that are not domain errors are logged and reported
+// as a bare Internal error.
+func toStatus(ctx context.Context, err error) error {
+	var domainErr *services.Error
+	if errors.As(err, &domainErr) {
+		code, ok := statusCodes[domainErr]
+		if !ok {
+			code = codes.FailedPrecondition
+		}
+		return statusError(ctx, code, domainErr.Code)
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		log.Printf("gRPC: %v", err)
+		return statusError(ctx, codes.DeadlineExceeded, errcode.Timeout)
+	case errors.Is(err, context.Canceled):
+		return statusError(ctx, codes.Canceled, errcode.Canceled)
+	}
+
+	log.Printf("gRPC: %v", err)
+	return statusError(ctx, codes.Internal, errcode.Internal)
+}
+
+// statusError builds a status worded in the language of the accept-language
+// metadata, with reason as its ErrorInfo detail.
+func statusError(ctx context.Context, code codes.Code, reason string) error {
+	lang := i18n.Negotiate(firstMetadata(ctx, "accept-language"))
+	st := status.New(code, i18n.Text(lang, reason))
+	if detailed, err := st.WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: errorDomain}); err == nil {
+		st = detailed
+	}
+
+	return st.Err()
+}
@@ -0,0 +1,235 @@
+// Package grpcapi serves the PVZ listing and the reception commands over gRPC,
+// backed by the same services as the HTTP API.
+package grpcapi
+
+//go:generate sh -c "cd ../../.. && buf generate"
+
+import (
+	"avito-internship/internal/auth"
+	"avito-internship/internal/models"
+	"avito-internship/internal/services"
+	"avito-internship/internal/transport/errcode"
+	"avito-internship/internal/transport/grpcapi/pvz_v1"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type server struct {
+	pvz_v1.UnimplementedPVZServiceServer
+
+	policy *auth.Policy
+	svc    services.Services
+	// pageSizes are the default and the largest GetPVZList page size.
+	pageSizes services.PageLimits
+}
+
+// NewServer returns a gRPC server with the PVZ service registered, checking
+// tokens and permissions the way the HTTP router does.
+func NewServer(tokens *auth.TokenManager, policy *auth.Policy, svc services.Services, pageSizes services.PageLimits) *grpc.Server {
+	s := grpc.NewServer(grpc.UnaryInterceptor(authInterceptor(tokens, policy, svc.Sessions)))
+	pvz_v1.RegisterPVZServiceServer(s, &server{policy: policy, svc: svc, pageSizes: pageSizes})
+	return s
+}
+
+func (s *server) GetPVZList(ctx context.Context, req *pvz_v1.GetPVZListRequest) (*pvz_v1.GetPVZListResponse, error) {
+	if req.PageSize < 0 || !validTimestamp(req.StartDate) || !validTimestamp(req.EndDate) {
+		return nil, statusError(ctx, codes.InvalidArgument, errcode.InvalidInput)
+	}
+	for _, city := range req.Cities {
+		if !oneOf(city, models.Cities) {
+			return nil, statusError(ctx, codes.InvalidArgument, errcode.InvalidInput)
+		}
+	}
+
+	filter := services.PVZFilter{
+		StartDate: optionalTime(req.StartDate),
+		EndDate:   optionalTime(req.EndDate),
+		Cities:    req.Cities,
+	}
+	if filter.StartDate != nil && filter.EndDate != nil && filter.EndDate.Before(*filter.StartDate) {
+		return nil, statusError(ctx, codes.InvalidArgument, errcode.InvalidInput)
+	}
+
+	limit := int(req.PageSize)
+	if limit == 0 {
+		limit = s.pageSizes.Default
+	}
+	if limit > s.pageSizes.Max {
+		limit = s.pageSizes.Max
+	}
+
+	list, next, err := s.svc.PVZ.ListPVZs(ctx, filter, req.PageToken, limit)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	resp := &pvz_v1.GetPVZListResponse{Items: make([]*pvz_v1.PVZWithReceptions, len(list)), NextPageToken: next}
+	for i, item := range list {
+		resp.Items[i] = toPVZWithReceptions(item)
+	}
+
+	return resp, nil
+}
+
+func (s *server) CreateReception(ctx context.Context, req *pvz_v1.CreateReceptionRequest) (*pvz_v1.Reception, error) {
+	if !isUUID(req.PvzId) {
+		return nil, statusError(ctx, codes.InvalidArgument, errcode.InvalidInput)
+	}
+	if err := s.authorizePVZ(ctx, req.PvzId); err != nil {
+		return nil, err
+	}
+
+	reception, err := s.svc.Receptions.CreateReception(ctx, req.PvzId, actorID(ctx))
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return toReception(*reception), nil
+}
+
+func (s *server) CloseLastReception(ctx context.Context, req *pvz_v1.CloseLastReceptionRequest) (*emptypb.Empty, error) {
+	if !isUUID(req.PvzId) {
+		return nil, statusError(ctx, codes.InvalidArgument, errcode.InvalidInput)
+	}
+	if err := s.authorizePVZ(ctx, req.PvzId); err != nil {
+		return nil, err
+	}
+
+	if err := s.svc.Receptions.CloseLastReception(ctx, req.PvzId, actorID(ctx)); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *server) AddProduct(ctx context.Context, req *pvz_v1.AddProductRequest) (*pvz_v1.Product, error) {
+	if !isUUID(req.PvzId) || !oneOf(req.Type, models.ProductTypes) {
+		return nil, statusError(ctx, codes.InvalidArgument, errcode.InvalidInput)
+	}
+	if err := s.authorizePVZ(ctx, req.PvzId); err != nil {
+		return nil, err
+	}
+
+	product, err := s.svc.Products.AddProduct(ctx, req.PvzId, req.Type, actorID(ctx))
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return toProduct(*product), nil
+}
+
+func (s *server) DeleteLastProduct(ctx context.Context, req *pvz_v1.DeleteLastProductRequest) (*emptypb.Empty, error) {
+	if !isUUID(req.PvzId) {
+		return nil, statusError(ctx, codes.InvalidArgument, errcode.InvalidInput)
+	}
+	if err := s.authorizePVZ(ctx, req.PvzId); err != nil {
+		return nil, err
+	}
+
+	if err := s.svc.Products.DeleteLastProduct(ctx, req.PvzId, actorID(ctx)); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// authorizePVZ limits employees to the PVZs they are assigned to, as the HTTP
+// handlers do.
+func (s *server) authorizePVZ(ctx context.Context, pvzID string) error {
+	claims := claimsFrom(ctx)
+	if !s.policy.IsPVZScoped(claims.Role) {
+		return nil
+	}
+
+	assigned, err := s.svc.Assignments.IsEmployeeAssigned(ctx, claims.UserID(), pvzID)
+	if err != nil {
+		return toStatus(ctx, err)
+	}
+	if !assigned {
+		return statusError(ctx, codes.PermissionDenied, errcode.PVZForbidden)
+	}
+
+	return nil
+}
+
+func toPVZ(p models.PVZ) *pvz_v1.PVZ {
+	return &pvz_v1.PVZ{
+		Id:               p.ID,
+		RegistrationDate: timestamppb.New(p.RegistrationDate),
+		City:             p.City,
+	}
+}
+
+func toPVZWithReceptions(item services.PVZWithReceptions) *pvz_v1.PVZWithReceptions {
+	receptions := make([]*pvz_v1.ReceptionWithProducts, len(item.Receptions))
+	for i, r := range item.Receptions {
+		products := make([]*pvz_v1.Product, len(r.Products))
+		for j, p := range r.Products {
+			products[j] = toProduct(p)
+		}
+		receptions[i] = &pvz_v1.ReceptionWithProducts{Reception: toReception(r.Reception), Products: products}
+	}
+
+	return &pvz_v1.PVZWithReceptions{Pvz: toPVZ(item.PVZ), Receptions: receptions}
+}
+
+func toReception(r models.Reception) *pvz_v1.Reception {
+	status := pvz_v1.ReceptionStatus_RECEPTION_STATUS_UNSPECIFIED
+	switch r.Status {
+	case "in_progress":
+		status = pvz_v1.ReceptionStatus_RECEPTION_STATUS_IN_PROGRESS
+	case "close":
+		status = pvz_v1.ReceptionStatus_RECEPTION_STATUS_CLOSED
+	}
+
+	return &pvz_v1.Reception{
+		Id:       r.ID,
+		DateTime: timestamppb.New(r.DateTime),
+		PvzId:    r.PVZID,
+		Status:   status,
+	}
+}
+
+func toProduct(p models.Product) *pvz_v1.Product {
+	return &pvz_v1.Product{
+		Id:          p.ID,
+		DateTime:    timestamppb.New(p.DateTime),
+		Type:        p.Type,
+		ReceptionId: p.ReceptionID,
+	}
+}
+
+// isUUID accepts the canonical hyphenated form only, like the uuid binding of
+// the HTTP API.
+func isUUID(s string) bool {
+	_, err := uuid.Parse(s)
+	return err == nil && len(s) == 36
+}
+
+// validTimestamp accepts unset timestamps too.
+func validTimestamp(ts *timestamppb.Timestamp) bool {
+	return ts == nil || ts.IsValid()
+}
+
+func optionalTime(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+func oneOf(v string, allowed []string) bool {
+	for _, a := range allowed {
+		if v == a {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,238 @@
+package grpcapi_test
+
+import (
+	"avito-internship/internal/app"
+	"avito-internship/internal/auth"
+	"avito-internship/internal/config"
+	"avito-internship/internal/models"
+	"avito-internship/internal/services"
+	"avito-internship/internal/transport/grpcapi"
+	"avito-internship/internal/transport/grpcapi/pvz_v1"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// newClient serves the API over an in-memory listener on the memory backend.
+func newClient(t *testing.T) (pvz_v1.PVZServiceClient, *auth.TokenManager, services.Services) {
+	t.Helper()
+
+	tokens, err := auth.NewTokenManager(map[string][]byte{"test": []byte("secret")}, "test", time.Hour, time.Hour)
+	require.NoError(t, err)
+	svc, err := app.NewServices(config.StorageMemory, config.DatabaseConfig{}, auth.NewPolicy(auth.DefaultPolicyConfig), true)
+	require.NoError(t, err)
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpcapi.NewServer(tokens, auth.NewPolicy(auth.DefaultPolicyConfig), svc, services.PageLimits{Default: 2, Max: 3})
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return pvz_v1.NewPVZServiceClient(conn), tokens, svc
+}
+
+func withToken(t *testing.T, tokens *auth.TokenManager, userID, role string) context.Context {
+	t.Helper()
+
+	token, err := tokens.Issue(userID, role, "")
+	require.NoError(t, err)
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func createPVZs(t *testing.T, svc services.Services, ids ...string) {
+	t.Helper()
+
+	for i, id := range ids {
+		_, err := svc.PVZ.CreatePVZ(context.Background(), models.PVZ{
+			ID:               id,
+			RegistrationDate: time.Date(2025, 4, 1+i, 12, 0, 0, 0, time.UTC),
+			City:             "Казань",
+		}, auth.DummyModeratorID)
+		require.NoError(t, err)
+	}
+}
+
+// requireReason asserts the status code and the ErrorInfo reason of err.
+func requireReason(t *testing.T, err error, code codes.Code, reason string) {
+	t.Helper()
+
+	st, ok := status.FromError(err)
+	require.True(t, ok, "not a status error: %v", err)
+	require.Equal(t, code, st.Code(), st.Message())
+	require.Len(t, st.Details(), 1)
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	assert.Equal(t, reason, info.Reason)
+}
+
+func TestGetPVZList(t *testing.T) {
+	client, tokens, svc := newClient(t)
+	createPVZs(t, svc,
+		"11111111-1111-1111-1111-111111111111",
+		"22222222-2222-2222-2222-222222222222",
+		"33333333-3333-3333-3333-333333333333")
+	ctx := withToken(t, tokens, auth.DummyModeratorID, auth.RoleModerator)
+
+	// Without a page size the default of 2 applies.
+	first, err := client.GetPVZList(ctx, &pvz_v1.GetPVZListRequest{})
+	require.NoError(t, err)
+	require.Len(t, first.Items, 2)
+	assert.Equal(t, "33333333-3333-3333-3333-333333333333", first.Items[0].Pvz.Id)
+	assert.Equal(t, "Казань", first.Items[0].Pvz.City)
+	assert.Equal(t, time.Date(2025, 4, 3, 12, 0, 0, 0, time.UTC), first.Items[0].Pvz.RegistrationDate.AsTime())
+	require.NotEmpty(t, first.NextPageToken)
+
+	second, err := client.GetPVZList(ctx, &pvz_v1.GetPVZListRequest{PageSize: 2, PageToken: first.NextPageToken})
+	require.NoError(t, err)
+	require.Len(t, second.Items, 1)
+	assert.Equal(t, "11111111-1111-1111-1111-111111111111", second.Items[0].Pvz.Id)
+	assert.Empty(t, second.NextPageToken)
+
+	// Larger page sizes are capped at the maximum of 3.
+	capped, err := client.GetPVZList(ctx, &pvz_v1.GetPVZListRequest{PageSize: 1000})
+	require.NoError(t, err)
+	assert.Len(t, capped.Items, 3)
+	assert.Empty(t, capped.NextPageToken)
+
+	_, err = client.GetPVZList(ctx, &pvz_v1.GetPVZListRequest{PageToken: "garbage"})
+	requireReason(t, err, codes.InvalidArgument, "invalid_cursor")
+}
+
+func TestGetPVZList_FiltersAndReceptions(t *testing.T) {
+	client, tokens, svc := newClient(t)
+	pvzID := "11111111-1111-1111-1111-111111111111"
+	createPVZs(t, svc, pvzID)
+	_, err := svc.PVZ.CreatePVZ(context.Background(), models.PVZ{
+		ID:               "22222222-2222-2222-2222-222222222222",
+		RegistrationDate: time.Date(2025, 4, 2, 12, 0, 0, 0, time.UTC),
+		City:             "Москва",
+	}, auth.DummyModeratorID)
+	require.NoError(t, err)
+	reception, err := svc.Receptions.CreateReception(context.Background(), pvzID, auth.DummyEmployeeID)
+	require.NoError(t, err)
+	product, err := svc.Products.AddProduct(context.Background(), pvzID, "обувь", auth.DummyEmployeeID)
+	require.NoError(t, err)
+	ctx := withToken(t, tokens, auth.DummyModeratorID, auth.RoleModerator)
+
+	resp, err := client.GetPVZList(ctx, &pvz_v1.GetPVZListRequest{Cities: []string{"Казань"}})
+	require.NoError(t, err)
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, pvzID, resp.Items[0].Pvz.Id)
+	require.Len(t, resp.Items[0].Receptions, 1)
+	assert.Equal(t, reception.ID, resp.Items[0].Receptions[0].Reception.Id)
+	require.Len(t, resp.Items[0].Receptions[0].Products, 1)
+	assert.Equal(t, product.ID, resp.Items[0].Receptions[0].Products[0].Id)
+
+	// Both PVZs were registered before the range.
+	resp, err = client.GetPVZList(ctx, &pvz_v1.GetPVZListRequest{StartDate: timestamppb.New(time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC))})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Items)
+
+	for _, req := range []*pvz_v1.GetPVZListRequest{
+		{Cities: []string{"Тверь"}},
+		{StartDate: timestamppb.New(time.Date(2025, 5, 2, 0, 0, 0, 0, time.UTC)), EndDate: timestamppb.New(time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC))},
+		{EndDate: &timestamppb.Timestamp{Nanos: -1}},
+	} {
+		_, err = client.GetPVZList(ctx, req)
+		requireReason(t, err, codes.InvalidArgument, "invalid_input")
+	}
+}
+
+func TestReceptionFlow(t *testing.T) {
+	client, tokens, svc := newClient(t)
+	pvzID := "11111111-1111-1111-1111-111111111111"
+	createPVZs(t, svc, pvzID)
+	require.NoError(t, svc.Assignments.AssignEmployee(context.Background(), pvzID, auth.DummyEmployeeID))
+	ctx := withToken(t, tokens, auth.DummyEmployeeID, auth.RoleEmployee)
+
+	reception, err := client.CreateReception(ctx, &pvz_v1.CreateReceptionRequest{PvzId: pvzID})
+	require.NoError(t, err)
+	assert.Equal(t, pvzID, reception.PvzId)
+	assert.Equal(t, pvz_v1.ReceptionStatus_RECEPTION_STATUS_IN_PROGRESS, reception.Status)
+
+	_, err = client.CreateReception(ctx, &pvz_v1.CreateReceptionRequest{PvzId: pvzID})
+	requireReason(t, err, codes.AlreadyExists, "reception_already_open")
+
+	product, err := client.AddProduct(ctx, &pvz_v1.AddProductRequest{PvzId: pvzID, Type: "обувь"})
+	require.NoError(t, err)
+	assert.Equal(t, reception.Id, product.ReceptionId)
+
+	_, err = client.AddProduct(ctx, &pvz_v1.AddProductRequest{PvzId: pvzID, Type: "еда"})
+	requireReason(t, err, codes.InvalidArgument, "invalid_input")
+
+	_, err = client.DeleteLastProduct(ctx, &pvz_v1.DeleteLastProductRequest{PvzId: pvzID})
+	require.NoError(t, err)
+
+	_, err = client.CloseLastReception(ctx, &pvz_v1.CloseLastReceptionRequest{PvzId: pvzID})
+	require.NoError(t, err)
+
+	_, err = client.CloseLastReception(ctx, &pvz_v1.CloseLastReceptionRequest{PvzId: pvzID})
+	requireReason(t, err, codes.FailedPrecondition, "no_active_reception")
+}
+
+func TestInvalidPVZID(t *testing.T) {
+	client, tokens, _ := newClient(t)
+	ctx := withToken(t, tokens, auth.DummyAdminID, auth.RoleAdmin)
+
+	_, err := client.CreateReception(ctx, &pvz_v1.CreateReceptionRequest{PvzId: "pvz-1"})
+	requireReason(t, err, codes.InvalidArgument, "invalid_input")
+
+	_, err = client.CloseLastReception(ctx, &pvz_v1.CloseLastReceptionRequest{PvzId: "{11111111-1111-1111-1111-111111111111}"})
+	requireReason(t, err, codes.InvalidArgument, "invalid_input")
+
+	_, err = client.AddProduct(ctx, &pvz_v1.AddProductRequest{PvzId: "123", Type: "обувь"})
+	requireReason(t, err, codes.InvalidArgument, "invalid_input")
+
+	_, err = client.DeleteLastProduct(ctx, &pvz_v1.DeleteLastProductRequest{})
+	requireReason(t, err, codes.InvalidArgument, "invalid_input")
+}
+
+func TestAuthorization(t *testing.T) {
+	client, tokens, svc := newClient(t)
+	pvzID := "11111111-1111-1111-1111-111111111111"
+	createPVZs(t, svc, pvzID)
+	req := &pvz_v1.CreateReceptionRequest{PvzId: pvzID}
+
+	_, err := client.CreateReception(context.Background(), req)
+	requireReason(t, err, codes.Unauthenticated, "missing_token")
+
+	_, err = client.GetPVZList(context.Background(), &pvz_v1.GetPVZListRequest{})
+	requireReason(t, err, codes.Unauthenticated, "missing_token")
+
+	bad := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer nope")
+	_, err = client.CreateReception(bad, req)
+	requireReason(t, err, codes.Unauthenticated, "invalid_token")
+
+	_, err = client.CreateReception(withToken(t, tokens, auth.DummyModeratorID, auth.RoleModerator), req)
+	requireReason(t, err, codes.PermissionDenied, "access_denied")
+
+	_, err = client.CreateReception(withToken(t, tokens, auth.DummyEmployeeID, auth.RoleEmployee), req)
+	requireReason(t, err, codes.PermissionDenied, "pvz_forbidden")
+}
+
+func TestErrorsAreLocalized(t *testing.T) {
+	client, _, _ := newClient(t)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "accept-language", "ru")
+	_, err := client.AddProduct(ctx, &pvz_v1.AddProductRequest{PvzId: "x", Type: "обувь"})
+
+	requireReason(t, err, codes.Unauthenticated, "missing_token")
+	assert.Equal(t, "Отсутствует токен", status.Convert(err).Message())
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"avito-internship/internal/auth"
+	"avito-internship/internal/models"
+	"avito-internship/internal/transport/errcode"
+	"context"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type AssignEmployeeRequest struct {
+	UserID string `json:"userId" binding:"required,uuid"`
+}
+
+type PVZURI struct {
+	PVZID string `uri:"pvzId" binding:"required,uuid"`
+}
+
+type PVZEmployeeURI struct {
+	PVZID  string `uri:"pvzId" binding:"required,uuid"`
+	UserID string `uri:"userId" binding:"omitempty,uuid"`
+}
+
+// PVZAccess answers whether an employee may act on a PVZ.
+type PVZAccess interface {
+	IsEmployeeAssigned(ctx context.Context, userID, pvzID string) (bool, error)
+}
+
+type AssignmentService interface {
+	PVZAccess
+	AssignEmployee(ctx context.Context, pvzID, userID string) error
+	UnassignEmployee(ctx context.Context, pvzID, userID string) error
+	GetPVZEmployees(ctx context.Context, pvzID string) ([]models.User, error)
+}
+
+func AssignEmployee(assignments AssignmentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var uri PVZEmployeeURI
+		var req AssignEmployeeRequest
+		if c.ShouldBindUri(&uri) != nil || c.ShouldBindJSON(&req) != nil {
+			respondInvalidInput(c, errcode.InvalidInput)
+			return
+		}
+
+		if err := assignments.AssignEmployee(c.Request.Context(), uri.PVZID, req.UserID); err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"pvzId": uri.PVZID, "userId": req.UserID})
+	}
+}
+
+func UnassignEmployee(assignments AssignmentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var uri PVZEmployeeURI
+		if err := c.ShouldBindUri(&uri); err != nil {
+			respondInvalidInput(c, errcode.InvalidInput)
+			return
+		}
+
+		if err := assignments.UnassignEmployee(c.Request.Context(), uri.PVZID, uri.UserID); err != nil {
+			respondError(c, err)
+			return
+		}
+
+		respondMessage(c, http.StatusOK, "ok.employee_unassigned")
+	}
+}
+
+func GetPVZEmployees(assignments AssignmentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var uri PVZEmployeeURI
+		if err := c.ShouldBindUri(&uri); err != nil {
+			respondInvalidInput(c, errcode.InvalidInput)
+			return
+		}
+
+		users, err := assignments.GetPVZEmployees(c.Request.Context(), uri.PVZID)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, users)
+	}
+}
+
+// authorizePVZ lets the request through only if the policy does not scope the
+// caller's role to assigned PVZs or the caller is assigned to the PVZ;
+// otherwise it writes the error response and returns false.
+func authorizePVZ(c *gin.Context, policy *auth.Policy, access PVZAccess, pvzID string) bool {
+	if !policy.IsPVZScoped(c.GetString("role")) {
+		return true
+	}
+
+	assigned, err := access.IsEmployeeAssigned(c.Request.Context(), c.GetString("userID"), pvzID)
+	if err != nil {
+		respondError(c, err)
+		return false
+	}
+
+	if !assigned {
+		respondCode(c, http.StatusForbidden, errcode.PVZForbidden)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,132 @@
+package handlers_test
+
+import (
+	"avito-internship/internal/transport/handlers"
+	"bytes"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+const testEmployeeID = "22222222-2222-2222-2222-222222222222"
+
+func expectAssigned(mock sqlmock.Sqlmock, pvzID string, assigned bool) {
+	mock.ExpectQuery(`FROM pvz_employees`).
+		WithArgs(testEmployeeID, pvzID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(assigned))
+}
+
+func setupAssignmentRouter(db *sql.DB) *gin.Engine {
+	assignments := newAssignmentService(db)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/pvz/:pvzId/employees", handlers.AssignEmployee(assignments))
+	router.DELETE("/pvz/:pvzId/employees/:userId", handlers.UnassignEmployee(assignments))
+	return router
+}
+
+func TestAssignEmployeeHandler_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	pvzID := "31ae2e29-0460-4748-a9f3-2b5747f78960"
+
+	mock.ExpectQuery(`SELECT id, email, password_hash, role FROM users`).
+		WithArgs(testEmployeeID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "password_hash", "role"}).
+			AddRow(testEmployeeID, "user@example.com", "hash", "employee"))
+	mock.ExpectExec(`INSERT INTO pvz_employees`).
+		WithArgs(pvzID, testEmployeeID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	router := setupAssignmentRouter(db)
+
+	body := `{"userId":"` + testEmployeeID + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/pvz/"+pvzID+"/employees", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusCreated, rr.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAssignEmployeeHandler_NotEmployee(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	pvzID := "31ae2e29-0460-4748-a9f3-2b5747f78960"
+
+	mock.ExpectQuery(`SELECT id, email, password_hash, role FROM users`).
+		WithArgs(testEmployeeID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "password_hash", "role"}).
+			AddRow(testEmployeeID, "user@example.com", "hash", "moderator"))
+
+	router := setupAssignmentRouter(db)
+
+	body := `{"userId":"` + testEmployeeID + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/pvz/"+pvzID+"/employees", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	require.Contains(t, rr.Body.String(), "User is not an employee")
+}
+
+func TestAssignEmployeeHandler_UnknownPVZ(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	pvzID := "31ae2e29-0460-4748-a9f3-2b5747f78960"
+
+	mock.ExpectQuery(`SELECT id, email, password_hash, role FROM users`).
+		WithArgs(testEmployeeID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "password_hash", "role"}).
+			AddRow(testEmployeeID, "user@example.com", "hash", "employee"))
+	mock.ExpectExec(`INSERT INTO pvz_employees`).
+		WithArgs(pvzID, testEmployeeID).
+		WillReturnError(&pq.Error{Code: "23503"})
+
+	router := setupAssignmentRouter(db)
+
+	body := `{"userId":"` + testEmployeeID + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/pvz/"+pvzID+"/employees", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusNotFound, rr.Code)
+	require.Contains(t, rr.Body.String(), `"code":"pvz_not_found"`)
+}
+
+func TestUnassignEmployeeHandler_NotAssigned(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	pvzID := "31ae2e29-0460-4748-a9f3-2b5747f78960"
+
+	mock.ExpectExec(`DELETE FROM pvz_employees`).
+		WithArgs(pvzID, testEmployeeID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	router := setupAssignmentRouter(db)
+
+	req := httptest.NewRequest(http.MethodDelete, "/pvz/"+pvzID+"/employees/"+testEmployeeID, nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	require.Contains(t, rr.Body.String(), "Employee is not assigned to this PVZ")
+}
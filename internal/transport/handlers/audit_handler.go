@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"avito-internship/internal/models"
+	"avito-internship/internal/services"
+	"avito-internship/internal/transport/errcode"
+	"context"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+type AuditQuery struct {
+	PVZID   string     `form:"pvzId" binding:"omitempty,uuid"`
+	ActorID string     `form:"actorId" binding:"omitempty,uuid"`
+	From    *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To      *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	// Page is capped so that the offset, at most 100 times larger, cannot
+	// overflow.
+	Page  int `form:"page,default=1" binding:"min=1,max=1000000"`
+	Limit int `form:"limit,default=50" binding:"min=1,max=100"`
+}
+
+type AuditService interface {
+	GetAuditLog(ctx context.Context, filter services.AuditFilter) ([]models.AuditEntry, error)
+}
+
+func GetAuditLog(audit AuditService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query AuditQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			respondInvalidInput(c, errcode.InvalidInput)
+			return
+		}
+
+		entries, err := audit.GetAuditLog(c.Request.Context(), services.AuditFilter{
+			PVZID:   query.PVZID,
+			ActorID: query.ActorID,
+			From:    query.From,
+			To:      query.To,
+			Page:    query.Page,
+			Limit:   query.Limit,
+		})
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, entries)
+	}
+}
@@ -0,0 +1,62 @@
+package handlers_test
+
+import (
+	"avito-internship/internal/transport/handlers"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAuditLogHandler_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	pvzID := "31ae2e29-0460-4748-a9f3-2b5747f78960"
+	from := time.Date(2025, 4, 21, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`FROM audit_log`).
+		WithArgs(pvzID, nil, from, nil, 50, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "occurred_at", "actor_id", "action", "entity_type", "entity_id", "pvz_id", "before", "after"}).
+			AddRow(int64(1), from, testEmployeeID, "reception.create", "reception", "rec-1", pvzID, nil, []byte(`{"id":"rec-1"}`)))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/audit", handlers.GetAuditLog(newAuditService(db)))
+
+	req := httptest.NewRequest(http.MethodGet, "/audit?pvzId="+pvzID+"&from=2025-04-21T00:00:00Z", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `[{
+		"id": 1,
+		"occurredAt": "2025-04-21T00:00:00Z",
+		"actorId": "`+testEmployeeID+`",
+		"action": "reception.create",
+		"entityType": "reception",
+		"entityId": "rec-1",
+		"pvzId": "`+pvzID+`",
+		"before": null,
+		"after": {"id": "rec-1"}
+	}]`, rr.Body.String())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAuditLogHandler_InvalidFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/audit", handlers.GetAuditLog(newAuditService(nil)))
+
+	for _, query := range []string{"actorId=not-a-uuid", "page=9223372036854775807"} {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/audit?"+query, nil))
+
+		require.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
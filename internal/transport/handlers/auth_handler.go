@@ -1,20 +1,202 @@
 package handlers
 
 import (
+	"avito-internship/internal/auth"
+	"avito-internship/internal/models"
+	"avito-internship/internal/transport/errcode"
+	"context"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"time"
 )
 
 type DummyLoginRequest struct {
-	Role string `json:"role" binding:"required,oneof=moderator employee"`
+	Role string `json:"role" binding:"required,oneof=moderator employee admin"`
 }
 
-func DummyLogin(c *gin.Context) {
-	var req DummyLoginRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid role"})
+// RegisterRequest signs up an employee. Role may be omitted; any other
+// role is created through CreateUserRequest.
+type RegisterRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,max=72"`
+	Role     string `json:"role" binding:"omitempty,eq=employee"`
+}
+
+type CreateUserRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,max=72"`
+	Role     string `json:"role" binding:"required"`
+}
+
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+type UserURI struct {
+	UserID string `uri:"userId" binding:"required,uuid"`
+}
+
+type UserService interface {
+	Register(ctx context.Context, email, password string) (*models.User, error)
+	CreateUser(ctx context.Context, email, password, role string) (*models.User, error)
+	Login(ctx context.Context, email, password string) (*models.User, error)
+}
+
+type SessionService interface {
+	CreateSession(ctx context.Context, user *models.User, ttl time.Duration) (*models.Session, string, error)
+	RefreshSession(ctx context.Context, refreshToken string, ttl time.Duration) (*models.Session, string, error)
+	RevokeSession(ctx context.Context, sessionID string) error
+	RevokeUserSessions(ctx context.Context, userID string) (int64, error)
+}
+
+var dummyUserIDs = map[string]string{
+	auth.RoleEmployee:  auth.DummyEmployeeID,
+	auth.RoleModerator: auth.DummyModeratorID,
+	auth.RoleAdmin:     auth.DummyAdminID,
+}
+
+// DummyLogin issues a token for a fixed per-role identity without checking
+// credentials. It is only routed outside production.
+func DummyLogin(tokens *auth.TokenManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req DummyLoginRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondInvalidInput(c, errcode.InvalidRole)
+			return
+		}
+
+		token, err := tokens.Issue(dummyUserIDs[req.Role], req.Role, "")
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"token": token})
+	}
+}
+
+func Register(users UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req RegisterRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondInvalidInput(c, errcode.InvalidInput)
+			return
+		}
+
+		user, err := users.Register(c.Request.Context(), req.Email, req.Password)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, user)
+	}
+}
+
+// CreateUser creates an account with the requested role, including the
+// privileged ones that cannot self-register.
+func CreateUser(users UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CreateUserRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondInvalidInput(c, errcode.InvalidInput)
+			return
+		}
+
+		user, err := users.CreateUser(c.Request.Context(), req.Email, req.Password, req.Role)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, user)
+	}
+}
+
+func Login(tokens *auth.TokenManager, users UserService, sessions SessionService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req LoginRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondInvalidInput(c, errcode.InvalidInput)
+			return
+		}
+
+		user, err := users.Login(c.Request.Context(), req.Email, req.Password)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		session, refreshToken, err := sessions.CreateSession(c.Request.Context(), user, tokens.RefreshTTL())
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		respondWithTokens(c, tokens, session, refreshToken)
+	}
+}
+
+func RefreshToken(tokens *auth.TokenManager, sessions SessionService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req RefreshRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondInvalidInput(c, errcode.InvalidInput)
+			return
+		}
+
+		session, refreshToken, err := sessions.RefreshSession(c.Request.Context(), req.RefreshToken, tokens.RefreshTTL())
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		respondWithTokens(c, tokens, session, refreshToken)
+	}
+}
+
+func Logout(sessions SessionService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if sessionID := c.GetString("sessionID"); sessionID != "" {
+			if err := sessions.RevokeSession(c.Request.Context(), sessionID); err != nil {
+				respondError(c, err)
+				return
+			}
+		}
+
+		respondMessage(c, http.StatusOK, "ok.logged_out")
+	}
+}
+
+func RevokeUserSessions(sessions SessionService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var uri UserURI
+		if err := c.ShouldBindUri(&uri); err != nil {
+			respondInvalidInput(c, errcode.InvalidInput)
+			return
+		}
+
+		revoked, err := sessions.RevokeUserSessions(c.Request.Context(), uri.UserID)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"revoked": revoked})
+	}
+}
+
+func respondWithTokens(c *gin.Context, tokens *auth.TokenManager, session *models.Session, refreshToken string) {
+	token, err := tokens.Issue(session.UserID, session.Role, session.ID)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"token": req.Role})
+	c.JSON(http.StatusOK, gin.H{"token": token, "refreshToken": refreshToken})
 }
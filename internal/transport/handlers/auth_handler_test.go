@@ -1,23 +1,44 @@
 package handlers_test
 
 import (
+	"avito-internship/internal/auth"
+	"avito-internship/internal/repository/postgres"
+	"avito-internship/internal/services"
 	"avito-internship/internal/transport/handlers"
 	"bytes"
+	"database/sql"
 	"encoding/json"
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
+var testTokens, _ = auth.NewTokenManager(map[string][]byte{"test": []byte("secret")}, "test", time.Hour, time.Hour)
+
 func setupRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
-	router.POST("/dummyLogin", handlers.DummyLogin)
+	router.POST("/dummyLogin", handlers.DummyLogin(testTokens))
 	return router
 }
 
+func tokenClaims(t *testing.T, body []byte) *auth.Claims {
+	var resp struct {
+		Token string `json:"token"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &resp))
+
+	claims, err := testTokens.Parse(resp.Token)
+	assert.NoError(t, err)
+	return claims
+}
+
 func TestDummyLogin_ValidModerator(t *testing.T) {
 	router := setupRouter()
 
@@ -30,7 +51,9 @@ func TestDummyLogin_ValidModerator(t *testing.T) {
 	router.ServeHTTP(response, request)
 
 	assert.Equal(t, http.StatusOK, response.Code)
-	assert.Contains(t, response.Body.String(), `"token":"moderator"`)
+	claims := tokenClaims(t, response.Body.Bytes())
+	assert.Equal(t, "moderator", claims.Role)
+	assert.Equal(t, auth.DummyModeratorID, claims.UserID())
 }
 
 func TestDummyLogin_ValidEmployee(t *testing.T) {
@@ -45,13 +68,15 @@ func TestDummyLogin_ValidEmployee(t *testing.T) {
 	router.ServeHTTP(response, request)
 
 	assert.Equal(t, http.StatusOK, response.Code)
-	assert.Contains(t, response.Body.String(), `"token":"employee"`)
+	claims := tokenClaims(t, response.Body.Bytes())
+	assert.Equal(t, "employee", claims.Role)
+	assert.Equal(t, auth.DummyEmployeeID, claims.UserID())
 }
 
 func TestDummyLogin_InvalidRole(t *testing.T) {
 	router := setupRouter()
 
-	body := map[string]string{"role": "admin"}
+	body := map[string]string{"role": "client"}
 	jsonBody, _ := json.Marshal(body)
 	request, _ := http.NewRequest(http.MethodPost, "/dummyLogin", bytes.NewBuffer(jsonBody))
 	request.Header.Set("Content-Type", "application/json")
@@ -77,3 +102,248 @@ func TestDummyLogin_MissingRole(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, response.Code)
 	assert.Contains(t, response.Body.String(), "Invalid role")
 }
+
+func setupAuthRouter(db *sql.DB) *gin.Engine {
+	users := services.NewUserService(postgres.NewUserRepository(db, time.Second), testPolicy)
+	sessions := newSessionService(db)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/register", handlers.Register(users))
+	router.POST("/users", handlers.CreateUser(users))
+	router.POST("/login", handlers.Login(testTokens, users, sessions))
+	router.POST("/token/refresh", handlers.RefreshToken(testTokens, sessions))
+	router.POST("/users/:userId/logout", handlers.RevokeUserSessions(sessions))
+	return router
+}
+
+func TestRegister_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`INSERT INTO users`).
+		WithArgs("user@example.com", sqlmock.AnyArg(), "employee").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("11111111-1111-1111-1111-111111111111"))
+
+	router := setupAuthRouter(db)
+
+	body := `{"email":"user@example.com","password":"secret","role":"employee"}`
+	request, _ := http.NewRequest(http.MethodPost, "/register", bytes.NewBufferString(body))
+	request.Header.Set("Content-Type", "application/json")
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusCreated, response.Code)
+	assert.JSONEq(t, `{"id":"11111111-1111-1111-1111-111111111111","email":"user@example.com","role":"employee"}`, response.Body.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRegister_InvalidInput(t *testing.T) {
+	router := setupAuthRouter(nil)
+
+	body := `{"email":"not-an-email","password":"secret","role":"employee"}`
+	request, _ := http.NewRequest(http.MethodPost, "/register", bytes.NewBufferString(body))
+	request.Header.Set("Content-Type", "application/json")
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Contains(t, response.Body.String(), "Invalid input")
+}
+
+func TestRegister_PrivilegedRole(t *testing.T) {
+	router := setupAuthRouter(nil)
+
+	body := `{"email":"user@example.com","password":"secret","role":"moderator"}`
+	request, _ := http.NewRequest(http.MethodPost, "/register", bytes.NewBufferString(body))
+	request.Header.Set("Content-Type", "application/json")
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Contains(t, response.Body.String(), `"code":"invalid_input"`)
+}
+
+// A password of 40 Cyrillic characters passes binding but is 80 bytes, more
+// than bcrypt accepts.
+func TestRegister_PasswordTooLong(t *testing.T) {
+	router := setupAuthRouter(nil)
+
+	body := `{"email":"user@example.com","password":"` + strings.Repeat("я", 40) + `"}`
+	request, _ := http.NewRequest(http.MethodPost, "/register", bytes.NewBufferString(body))
+	request.Header.Set("Content-Type", "application/json")
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Contains(t, response.Body.String(), `"code":"invalid_input"`)
+}
+
+func TestCreateUser_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`INSERT INTO users`).
+		WithArgs("mod@example.com", sqlmock.AnyArg(), "moderator").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("11111111-1111-1111-1111-111111111111"))
+
+	router := setupAuthRouter(db)
+
+	body := `{"email":"mod@example.com","password":"secret","role":"moderator"}`
+	request, _ := http.NewRequest(http.MethodPost, "/users", bytes.NewBufferString(body))
+	request.Header.Set("Content-Type", "application/json")
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusCreated, response.Code)
+	assert.JSONEq(t, `{"id":"11111111-1111-1111-1111-111111111111","email":"mod@example.com","role":"moderator"}`, response.Body.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateUser_UnknownRole(t *testing.T) {
+	router := setupAuthRouter(nil)
+
+	body := `{"email":"user@example.com","password":"secret","role":"client"}`
+	request, _ := http.NewRequest(http.MethodPost, "/users", bytes.NewBufferString(body))
+	request.Header.Set("Content-Type", "application/json")
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Contains(t, response.Body.String(), `"code":"role_not_allowed"`)
+}
+
+func TestLogin_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	mock.ExpectQuery(`SELECT id, email, password_hash, role FROM users`).
+		WithArgs("user@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "password_hash", "role"}).
+			AddRow("user-id", "user@example.com", string(hash), "moderator"))
+	mock.ExpectQuery(`INSERT INTO sessions`).
+		WithArgs("user-id", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("session-id"))
+
+	router := setupAuthRouter(db)
+
+	body := `{"email":"user@example.com","password":"secret"}`
+	request, _ := http.NewRequest(http.MethodPost, "/login", bytes.NewBufferString(body))
+	request.Header.Set("Content-Type", "application/json")
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	claims := tokenClaims(t, response.Body.Bytes())
+	assert.Equal(t, "moderator", claims.Role)
+	assert.Equal(t, "user-id", claims.UserID())
+	assert.Equal(t, "session-id", claims.SessionID)
+	assert.Contains(t, response.Body.String(), `"refreshToken"`)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLogin_InvalidCredentials(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	mock.ExpectQuery(`SELECT id, email, password_hash, role FROM users`).
+		WithArgs("user@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "password_hash", "role"}).
+			AddRow("user-id", "user@example.com", string(hash), "moderator"))
+
+	router := setupAuthRouter(db)
+
+	body := `{"email":"user@example.com","password":"wrong"}`
+	request, _ := http.NewRequest(http.MethodPost, "/login", bytes.NewBufferString(body))
+	request.Header.Set("Content-Type", "application/json")
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	assert.Contains(t, response.Body.String(), `"code":"invalid_credentials"`)
+}
+
+func TestRefreshToken_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`UPDATE sessions s`).
+		WithArgs(auth.HashRefreshToken("refresh"), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "role"}).AddRow("session-id", "user-id", "employee"))
+
+	router := setupAuthRouter(db)
+
+	request, _ := http.NewRequest(http.MethodPost, "/token/refresh", bytes.NewBufferString(`{"refreshToken":"refresh"}`))
+	request.Header.Set("Content-Type", "application/json")
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	claims := tokenClaims(t, response.Body.Bytes())
+	assert.Equal(t, "employee", claims.Role)
+	assert.Equal(t, "session-id", claims.SessionID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshToken_Invalid(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`UPDATE sessions s`).
+		WithArgs(auth.HashRefreshToken("stale"), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "role"}))
+
+	router := setupAuthRouter(db)
+
+	request, _ := http.NewRequest(http.MethodPost, "/token/refresh", bytes.NewBufferString(`{"refreshToken":"stale"}`))
+	request.Header.Set("Content-Type", "application/json")
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	assert.Contains(t, response.Body.String(), `"code":"invalid_refresh_token"`)
+}
+
+func TestRevokeUserSessions_Success(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	userID := "11111111-1111-1111-1111-111111111111"
+	mock.ExpectExec(`UPDATE sessions`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	router := setupAuthRouter(db)
+
+	request, _ := http.NewRequest(http.MethodPost, "/users/"+userID+"/logout", nil)
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"revoked":2}`, response.Body.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"avito-internship/internal/i18n"
+	"avito-internship/internal/models"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// DictionaryEntry pairs a domain value, as sent to and returned by the API,
+// with its display name.
+type DictionaryEntry struct {
+	Value string `json:"value"`
+	Name  string `json:"name"`
+}
+
+type DictionaryResponse struct {
+	Cities            []DictionaryEntry `json:"cities"`
+	ProductTypes      []DictionaryEntry `json:"productTypes"`
+	ReceptionStatuses []DictionaryEntry `json:"receptionStatuses"`
+}
+
+// GetDictionary lists the accepted domain values with display names in the
+// negotiated language.
+func GetDictionary() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := language(c)
+		entries := func(values []string) []DictionaryEntry {
+			list := make([]DictionaryEntry, len(values))
+			for i, v := range values {
+				list[i] = DictionaryEntry{Value: v, Name: i18n.Name(lang, v)}
+			}
+			return list
+		}
+
+		c.JSON(http.StatusOK, DictionaryResponse{
+			Cities:            entries(models.Cities),
+			ProductTypes:      entries(models.ProductTypes),
+			ReceptionStatuses: entries(models.ReceptionStatuses),
+		})
+	}
+}
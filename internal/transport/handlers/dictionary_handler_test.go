@@ -0,0 +1,39 @@
+package handlers_test
+
+import (
+	"avito-internship/internal/transport/handlers"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDictionary(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/dictionary", handlers.GetDictionary())
+
+	cases := map[string]handlers.DictionaryEntry{
+		"ru": {Value: "in_progress", Name: "В процессе"},
+		"en": {Value: "in_progress", Name: "In progress"},
+		"":   {Value: "in_progress", Name: "In progress"},
+	}
+
+	for lang, want := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/dictionary", nil)
+		req.Header.Set("Accept-Language", lang)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var body handlers.DictionaryResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Contains(t, body.ReceptionStatuses, want, "Accept-Language: %q", lang)
+		assert.Len(t, body.Cities, 3)
+		assert.Len(t, body.ProductTypes, 3)
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"avito-internship/internal/i18n"
+	"avito-internship/internal/services"
+	"avito-internship/internal/transport/errcode"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorResponse is the body of every error response.
+type ErrorResponse struct {
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Errors  []FieldError `json:"errors,omitempty"`
+}
+
+// errorStatus maps domain errors to HTTP statuses, in line with the gRPC
+// codes of grpcapi. Business rule violations not listed here are reported as
+// 400, which is what the API has always returned for them.
+var errorStatus = map[*services.Error]int{
+	services.ErrInvalidCredentials:   http.StatusUnauthorized,
+	services.ErrInvalidRefreshToken:  http.StatusUnauthorized,
+	services.ErrPVZNotFound:          http.StatusNotFound,
+	services.ErrUserNotFound:         http.StatusNotFound,
+	services.ErrPVZExists:            http.StatusConflict,
+	services.ErrUserExists:           http.StatusConflict,
+	services.ErrReceptionAlreadyOpen: http.StatusConflict,
+}
+
+// respondError writes err as an ErrorResponse. A query that ran out of time
+// is reported as 504 and one abandoned because the request was canceled as
+// 503. Other errors that are not domain errors are logged and reported as a
+// bare 500 so that driver messages never reach clients.
+func respondError(c *gin.Context, err error) {
+	var domainErr *services.Error
+	if errors.As(err, &domainErr) {
+		status, ok := errorStatus[domainErr]
+		if !ok {
+			status = http.StatusBadRequest
+		}
+
+		respondCode(c, status, domainErr.Code)
+		return
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		log.Printf("%s %s: %v", c.Request.Method, c.FullPath(), err)
+		respondCode(c, http.StatusGatewayTimeout, errcode.Timeout)
+		return
+	case errors.Is(err, context.Canceled):
+		respondCode(c, http.StatusServiceUnavailable, errcode.Canceled)
+		return
+	}
+
+	log.Printf("%s %s: %v", c.Request.Method, c.FullPath(), err)
+	respondCode(c, http.StatusInternalServerError, errcode.Internal)
+}
+
+// AbortWithError is respondError for middleware: it reports err the same way
+// and stops the handler chain.
+func AbortWithError(c *gin.Context, err error) {
+	respondError(c, err)
+	c.Abort()
+}
+
+// respondCode writes an ErrorResponse whose message is the text of code in
+// the negotiated language.
+func respondCode(c *gin.Context, status int, code string) {
+	c.JSON(status, ErrorResponse{Code: code, Message: i18n.Text(language(c), code)})
+}
+
+// respondInvalidInput writes a 400 invalid_input response with the text under
+// messageKey as its message.
+func respondInvalidInput(c *gin.Context, messageKey string, fields ...FieldError) {
+	c.JSON(http.StatusBadRequest, ErrorResponse{Code: errcode.InvalidInput, Message: i18n.Text(language(c), messageKey), Errors: fields})
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadinessProbe reports whether the instance may receive traffic.
+type ReadinessProbe interface {
+	Draining() bool
+	Check(ctx context.Context) map[string]error
+}
+
+// HealthResponse is the body of /healthz and /readyz. Checks marks the failed
+// dependencies; the errors themselves are only logged.
+type HealthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Liveness answers as long as the process serves requests.
+func Liveness() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, HealthResponse{Status: "ok"})
+	}
+}
+
+// Readiness answers 503 while draining or while a dependency check fails or
+// takes longer than timeout.
+func Readiness(probe ReadinessProbe, timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if probe.Draining() {
+			c.JSON(http.StatusServiceUnavailable, HealthResponse{Status: "draining"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		failed := probe.Check(ctx)
+		checks := map[string]string{}
+		for name, err := range failed {
+			log.Printf("readiness check %s failed: %v", name, err)
+			checks[name] = "failed"
+		}
+
+		if len(failed) > 0 {
+			c.JSON(http.StatusServiceUnavailable, HealthResponse{Status: "not_ready", Checks: checks})
+			return
+		}
+
+		c.JSON(http.StatusOK, HealthResponse{Status: "ready"})
+	}
+}
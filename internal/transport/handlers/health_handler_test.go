@@ -0,0 +1,66 @@
+package handlers_test
+
+import (
+	"avito-internship/internal/health"
+	"avito-internship/internal/transport/handlers"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func getHealth(handler gin.HandlerFunc) (int, handlers.HealthResponse) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/probe", handler)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/probe", nil))
+
+	var body handlers.HealthResponse
+	json.Unmarshal(w.Body.Bytes(), &body)
+	return w.Code, body
+}
+
+func TestLiveness(t *testing.T) {
+	code, body := getHealth(handlers.Liveness())
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok", body.Status)
+}
+
+func TestReadiness(t *testing.T) {
+	dbErr := errors.New("connection refused")
+	dbCheck := health.Check{Name: "database", Run: func(context.Context) error { return dbErr }}
+
+	code, body := getHealth(handlers.Readiness(health.NewReadiness(dbCheck), time.Second))
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, handlers.HealthResponse{Status: "not_ready", Checks: map[string]string{"database": "failed"}}, body)
+
+	dbErr = nil
+	readiness := health.NewReadiness(dbCheck)
+	code, body = getHealth(handlers.Readiness(readiness, time.Second))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ready", body.Status)
+
+	readiness.Drain()
+	code, body = getHealth(handlers.Readiness(readiness, time.Second))
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "draining", body.Status)
+}
+
+func TestReadiness_Timeout(t *testing.T) {
+	slow := health.Check{Name: "database", Run: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+
+	code, body := getHealth(handlers.Readiness(health.NewReadiness(slow), 10*time.Millisecond))
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, map[string]string{"database": "failed"}, body.Checks)
+}
@@ -0,0 +1,27 @@
+package handlers_test
+
+import (
+	"avito-internship/internal/auth"
+	"avito-internship/internal/repository/postgres"
+	"avito-internship/internal/services"
+	"database/sql"
+	"time"
+)
+
+// testPolicy scopes employees to the PVZs they are assigned to.
+var testPolicy = auth.NewPolicy(auth.DefaultPolicyConfig)
+
+// The handler tests below drive the real services over the Postgres
+// repositories, with db being a sqlmock connection.
+
+func newAssignmentService(db *sql.DB) *services.AssignmentService {
+	return services.NewAssignmentService(postgres.NewUserRepository(db, time.Second), postgres.NewAssignmentRepository(db, time.Second), testPolicy)
+}
+
+func newSessionService(db *sql.DB) *services.SessionService {
+	return services.NewSessionService(postgres.NewSessionRepository(db, time.Second))
+}
+
+func newAuditService(db *sql.DB) *services.AuditService {
+	return services.NewAuditService(postgres.NewAuditRepository(db, time.Second))
+}
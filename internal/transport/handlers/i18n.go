@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"avito-internship/internal/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// language returns the language negotiated from the Accept-Language header
+// and announces it in Content-Language.
+func language(c *gin.Context) string {
+	lang := i18n.Negotiate(c.GetHeader("Accept-Language"))
+	c.Header("Content-Language", lang)
+	return lang
+}
+
+// respondMessage writes a {"message": ...} body with the text under key.
+func respondMessage(c *gin.Context, status int, key string) {
+	c.JSON(status, gin.H{"message": i18n.Text(language(c), key)})
+}
@@ -1,52 +1,64 @@
 package handlers
 
 import (
-	"avito-internship/internal/database"
-	"avito-internship/internal/services"
+	"avito-internship/internal/auth"
+	"avito-internship/internal/models"
+	"avito-internship/internal/transport/errcode"
+	"context"
 	"github.com/gin-gonic/gin"
 	"net/http"
 )
 
 type AddProductRequest struct {
 	Type  string `json:"type" binding:"required,oneof=электроника одежда обувь"`
-	PVZID string `json:"pvzId" binding:"required"`
+	PVZID string `json:"pvzId" binding:"required,uuid"`
 }
 
-func AddProduct(c *gin.Context) {
-	role := c.GetString("role")
-	if role != "employee" {
-		c.JSON(http.StatusForbidden, gin.H{"message": "Only employees can add products"})
-		return
-	}
+type ProductService interface {
+	AddProduct(ctx context.Context, pvzID, productType, actorID string) (*models.Product, error)
+	DeleteLastProduct(ctx context.Context, pvzID, actorID string) error
+}
 
-	var req AddProductRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input"})
-		return
-	}
+func AddProduct(products ProductService, policy *auth.Policy, access PVZAccess) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req AddProductRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondInvalidInput(c, errcode.InvalidInput)
+			return
+		}
 
-	product, err := services.AddProduct(database.DB, req.PVZID, req.Type)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
-		return
-	}
+		if !authorizePVZ(c, policy, access, req.PVZID) {
+			return
+		}
 
-	c.JSON(http.StatusCreated, product)
-}
+		product, err := products.AddProduct(c.Request.Context(), req.PVZID, req.Type, c.GetString("userID"))
+		if err != nil {
+			respondError(c, err)
+			return
+		}
 
-func DeleteLastProduct(c *gin.Context) {
-	role := c.GetString("role")
-	if role != "employee" {
-		c.JSON(http.StatusForbidden, gin.H{"message": "Only employees can delete products"})
-		return
+		c.JSON(http.StatusCreated, product)
 	}
+}
 
-	pvzID := c.Param("pvzId")
-	err := services.DeleteLastProduct(database.DB, pvzID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
-		return
-	}
+func DeleteLastProduct(products ProductService, policy *auth.Policy, access PVZAccess) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var uri PVZURI
+		if err := c.ShouldBindUri(&uri); err != nil {
+			respondInvalidInput(c, errcode.InvalidInput)
+			return
+		}
+
+		if !authorizePVZ(c, policy, access, uri.PVZID) {
+			return
+		}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Last product deleted successfully"})
+		err := products.DeleteLastProduct(c.Request.Context(), uri.PVZID, c.GetString("userID"))
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		respondMessage(c, http.StatusOK, "ok.product_deleted")
+	}
 }
@@ -1,7 +1,10 @@
 package handlers_test
 
 import (
+	"avito-internship/internal/transport/errcode"
 	"bytes"
+	"context"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -10,6 +13,7 @@ import (
 	"testing"
 
 	"avito-internship/internal/models"
+	"avito-internship/internal/services"
 	"avito-internship/internal/transport/handlers"
 )
 
@@ -17,19 +21,21 @@ type mockService struct {
 	mock.Mock
 }
 
-func (m *mockService) AddProduct(pvzID, typ string) (*models.Product, error) {
-	args := m.Called(pvzID, typ)
+func (m *mockService) AddProduct(_ context.Context, pvzID, typ, actorID string) (*models.Product, error) {
+	args := m.Called(pvzID, typ, actorID)
 	if args.Get(0) != nil {
 		return args.Get(0).(*models.Product), args.Error(1)
 	}
 	return nil, args.Error(1)
 }
 
-func (m *mockService) DeleteLastProduct(pvzID string) error {
-	args := m.Called(pvzID)
+func (m *mockService) DeleteLastProduct(_ context.Context, pvzID, actorID string) error {
+	args := m.Called(pvzID, actorID)
 	return args.Error(0)
 }
 
+const testPVZID = "31ae2e29-0460-4748-a9f3-2b5747f78960"
+
 func setupRouterWithService(service *mockService) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
@@ -41,12 +47,9 @@ func setupRouterWithService(service *mockService) *gin.Engine {
 		c.Next()
 	})
 
-	r.POST("/products", func(c *gin.Context) {
-		handlers.AddProduct(c)
-	})
-	r.DELETE("/products/:pvzId", func(c *gin.Context) {
-		handlers.DeleteLastProduct(c)
-	})
+	access := newAssignmentService(nil)
+	r.POST("/products", handlers.AddProduct(service, testPolicy, access))
+	r.DELETE("/products/:pvzId", handlers.DeleteLastProduct(service, testPolicy, access))
 
 	return r
 }
@@ -68,11 +71,34 @@ func TestAddProduct_InvalidInput(t *testing.T) {
 	require.Contains(t, w.Body.String(), "Invalid input")
 }
 
-func TestAddProduct_Forbidden(t *testing.T) {
+func TestProductHandlers_InvalidPVZID(t *testing.T) {
+	mockSvc := new(mockService)
+	router := setupRouterWithService(mockSvc)
+
+	req := httptest.NewRequest(http.MethodPost, "/products", bytes.NewBufferString(`{"type":"обувь","pvzId":"pvz-1"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), `"code":"invalid_input"`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/products/pvz-1", nil))
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), `"code":"invalid_input"`)
+	mockSvc.AssertNotCalled(t, "AddProduct")
+	mockSvc.AssertNotCalled(t, "DeleteLastProduct")
+}
+
+func TestAddProduct_Success(t *testing.T) {
 	mockSvc := new(mockService)
+	mockSvc.On("AddProduct", testPVZID, "обувь", "").
+		Return(&models.Product{ID: "prod-1", Type: "обувь", ReceptionID: "rec-1"}, nil)
 	router := setupRouterWithService(mockSvc)
 
-	body := []byte(`{"type":"электроника","pvzId":"test-pvz"}`)
+	body := []byte(`{"type":"обувь","pvzId":"` + testPVZID + `"}`)
 
 	req := httptest.NewRequest(http.MethodPost, "/products", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
@@ -81,20 +107,66 @@ func TestAddProduct_Forbidden(t *testing.T) {
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
-	require.Equal(t, http.StatusForbidden, w.Code)
-	require.Contains(t, w.Body.String(), "Only employees can add products")
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Contains(t, w.Body.String(), "prod-1")
+	mockSvc.AssertExpectations(t)
 }
 
-func TestDeleteLastProduct_Forbidden(t *testing.T) {
+func TestDeleteLastProduct_ServiceError(t *testing.T) {
 	mockSvc := new(mockService)
+	mockSvc.On("DeleteLastProduct", testPVZID, "").
+		Return(services.ErrNoProductsToDelete)
 	router := setupRouterWithService(mockSvc)
 
-	req := httptest.NewRequest(http.MethodDelete, "/products/test-pvz", nil)
+	req := httptest.NewRequest(http.MethodDelete, "/products/"+testPVZID, nil)
 	req.Header.Set("Role", "moderator")
 
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
-	require.Equal(t, http.StatusForbidden, w.Code)
-	require.Contains(t, w.Body.String(), "Only employees can delete products")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), `"code":"no_products_to_delete"`)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestDeleteLastProduct_InternalErrorIsHidden(t *testing.T) {
+	mockSvc := new(mockService)
+	mockSvc.On("DeleteLastProduct", testPVZID, "").
+		Return(fmt.Errorf("pq: connection refused"))
+	router := setupRouterWithService(mockSvc)
+
+	req := httptest.NewRequest(http.MethodDelete, "/products/"+testPVZID, nil)
+	req.Header.Set("Role", "moderator")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	require.JSONEq(t, `{"code":"internal_error","message":"Internal server error"}`, w.Body.String())
+	mockSvc.AssertExpectations(t)
+}
+
+func TestDeleteLastProduct_QueryAborted(t *testing.T) {
+	cases := map[error]struct {
+		status int
+		code   string
+	}{
+		context.DeadlineExceeded: {http.StatusGatewayTimeout, errcode.Timeout},
+		context.Canceled:         {http.StatusServiceUnavailable, errcode.Canceled},
+	}
+
+	for err, want := range cases {
+		mockSvc := new(mockService)
+		mockSvc.On("DeleteLastProduct", testPVZID, "").Return(err)
+		router := setupRouterWithService(mockSvc)
+
+		req := httptest.NewRequest(http.MethodDelete, "/products/"+testPVZID, nil)
+		req.Header.Set("Role", "moderator")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, want.status, w.Code, err.Error())
+		require.Contains(t, w.Body.String(), `"code":"`+want.code+`"`)
+	}
 }
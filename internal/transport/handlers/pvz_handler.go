@@ -1,12 +1,16 @@
 package handlers
 
 import (
-	"avito-internship/internal/database"
+	"avito-internship/internal/i18n"
 	"avito-internship/internal/models"
 	"avito-internship/internal/services"
+	"avito-internship/internal/transport/errcode"
+	"context"
+	"errors"
 	"github.com/gin-gonic/gin"
+	"math"
 	"net/http"
-	"strconv"
+	"strings"
 	"time"
 )
 
@@ -16,69 +20,223 @@ type CreatePVZRequest struct {
 	City             string    `json:"city" binding:"required"`
 }
 
-func CreatePVZ(c *gin.Context) {
-	role := c.GetString("role")
-	if role != "moderator" {
-		c.JSON(http.StatusForbidden, gin.H{"message": "Only moderators can create PVZ"})
-		return
-	}
+type PVZService interface {
+	CreatePVZ(ctx context.Context, pvz models.PVZ, actorID string) (*models.PVZ, error)
+	GetPVZList(ctx context.Context, filter services.PVZFilter, page, limit int) ([]services.PVZWithReceptions, error)
+	GetPVZPageByNumber(ctx context.Context, filter services.PVZFilter, page, limit int) (*services.PVZPage, error)
+	GetPVZPage(ctx context.Context, filter services.PVZFilter, cursor string, limit int) (*services.PVZPage, error)
+}
 
-	var req CreatePVZRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input"})
-		return
-	}
+// LegacyPVZListMediaType in the Accept header selects the original bare array
+// response of GET /pvz, without totals or aggregates.
+const LegacyPVZListMediaType = "application/vnd.pvz.v1+json"
 
-	pvz := models.PVZ{
-		ID:               req.ID,
-		City:             req.City,
-		RegistrationDate: req.RegistrationDate,
-	}
+type PVZListItem struct {
+	PVZ            PVZResponse                     `json:"pvz"`
+	CityName       string                          `json:"cityName"`
+	Receptions     []ReceptionWithProductsResponse `json:"receptions"`
+	ReceptionCount int                             `json:"receptionCount"`
+	ProductCounts  map[string]int                  `json:"productCounts"`
+}
 
-	result, err := services.CreatePVZ(database.DB, pvz)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
-		return
-	}
+type PVZResponse struct {
+	ID               string    `json:"id"`
+	RegistrationDate time.Time `json:"registrationDate"`
+	City             string    `json:"city"`
+}
 
-	c.JSON(http.StatusCreated, result)
+type ReceptionWithProductsResponse struct {
+	Reception ReceptionResponse `json:"reception"`
+	Products  []ProductResponse `json:"products"`
 }
 
-func GetPVZList(c *gin.Context) {
-	role := c.GetString("role")
-	if role != "employee" && role != "moderator" {
-		c.JSON(http.StatusForbidden, gin.H{"message": "Access denied"})
-		return
-	}
+type ReceptionResponse struct {
+	ID         string     `json:"id"`
+	DateTime   time.Time  `json:"dateTime"`
+	PVZID      string     `json:"pvzId"`
+	Status     string     `json:"status"`
+	StatusName string     `json:"statusName"`
+	CreatedBy  *string    `json:"createdBy"`
+	ClosedBy   *string    `json:"closedBy"`
+	ClosedAt   *time.Time `json:"closedAt"`
+}
 
-	layout := time.RFC3339
-	var startDate, endDate *time.Time
+// ProductResponse carries the deletion fields, which are only set when the
+// listing includes deleted products.
+type ProductResponse struct {
+	ID          string     `json:"id"`
+	DateTime    time.Time  `json:"dateTime"`
+	Type        string     `json:"type"`
+	TypeName    string     `json:"typeName"`
+	ReceptionID string     `json:"receptionId"`
+	CreatedBy   *string    `json:"createdBy"`
+	DeletedBy   *string    `json:"deletedBy"`
+	DeletedAt   *time.Time `json:"deletedAt"`
+}
+
+// PVZListResponse is the envelope of GET /pvz. Page and TotalPages are omitted
+// when paging with cursors.
+type PVZListResponse struct {
+	Items      []PVZListItem `json:"items"`
+	Total      int           `json:"total"`
+	Page       int           `json:"page,omitempty"`
+	TotalPages int           `json:"totalPages,omitempty"`
+	Limit      int           `json:"limit"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+}
 
-	if s := c.Query("startDate"); s != "" {
-		t, err := time.Parse(layout, s)
-		if err == nil {
-			startDate = &t
+func newPVZListResponse(p *services.PVZPage, limit int, lang string) PVZListResponse {
+	items := make([]PVZListItem, len(p.Items))
+	for i, item := range p.Items {
+		stats := p.Stats[item.PVZ.ID]
+		counts := stats.ProductCounts
+		if counts == nil {
+			counts = map[string]int{}
 		}
-	}
 
-	if e := c.Query("endDate"); e != "" {
-		t, err := time.Parse(layout, e)
-		if err == nil {
-			endDate = &t
+		items[i] = PVZListItem{
+			PVZ:            newPVZResponse(item.PVZ),
+			CityName:       i18n.Name(lang, item.PVZ.City),
+			Receptions:     newReceptionResponses(item.Receptions, lang),
+			ReceptionCount: stats.ReceptionCount,
+			ProductCounts:  counts,
 		}
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	if limit > 30 {
-		limit = 30
+	return PVZListResponse{Items: items, Total: p.Total, Limit: limit, NextCursor: p.NextCursor}
+}
+
+func newPVZResponse(pvz models.PVZ) PVZResponse {
+	return PVZResponse{ID: pvz.ID, RegistrationDate: pvz.RegistrationDate, City: pvz.City}
+}
+
+func newReceptionResponses(receptions []services.ReceptionWithProducts, lang string) []ReceptionWithProductsResponse {
+	result := make([]ReceptionWithProductsResponse, len(receptions))
+	for i, r := range receptions {
+		products := make([]ProductResponse, len(r.Products))
+		for j, p := range r.Products {
+			products[j] = ProductResponse{
+				ID:          p.ID,
+				DateTime:    p.DateTime,
+				Type:        p.Type,
+				TypeName:    i18n.Name(lang, p.Type),
+				ReceptionID: p.ReceptionID,
+				CreatedBy:   p.CreatedBy,
+				DeletedBy:   p.DeletedBy,
+				DeletedAt:   p.DeletedAt,
+			}
+		}
+
+		result[i] = ReceptionWithProductsResponse{
+			Reception: ReceptionResponse{
+				ID:         r.Reception.ID,
+				DateTime:   r.Reception.DateTime,
+				PVZID:      r.Reception.PVZID,
+				Status:     r.Reception.Status,
+				StatusName: i18n.Name(lang, r.Reception.Status),
+				CreatedBy:  r.Reception.CreatedBy,
+				ClosedBy:   r.Reception.ClosedBy,
+				ClosedAt:   r.Reception.ClosedAt,
+			},
+			Products: products,
+		}
 	}
 
-	result, err := services.GetPVZList(database.DB, startDate, endDate, page, limit)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
-		return
+	return result
+}
+
+func CreatePVZ(pvzs PVZService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CreatePVZRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondInvalidInput(c, errcode.InvalidInput)
+			return
+		}
+
+		pvz := models.PVZ{
+			ID:               req.ID,
+			City:             req.City,
+			RegistrationDate: req.RegistrationDate,
+		}
+
+		result, err := pvzs.CreatePVZ(c.Request.Context(), pvz, c.GetString("userID"))
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, result)
 	}
+}
 
-	c.JSON(http.StatusOK, result)
+func GetPVZList(pvzs PVZService, limits services.PageLimits) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		q := newQueryParser(c)
+
+		filter := services.PVZFilter{
+			StartDate:       q.date("startDate", false),
+			EndDate:         q.date("endDate", true),
+			Cities:          q.enumList("city", models.Cities...),
+			ReceptionStatus: q.enum("receptionStatus", models.ReceptionStatuses...),
+			ProductType:     q.enum("productType", models.ProductTypes...),
+			IncludeDeleted:  q.flag("includeDeleted"),
+		}
+		if filter.StartDate != nil && filter.EndDate != nil && filter.EndDate.Before(*filter.StartDate) {
+			q.fail("endDate", "field.not_before", "startDate")
+		}
+
+		page := q.integer("page", 1, 1)
+		limit := q.integer("limit", limits.Default, 1)
+		// Larger pages are capped rather than rejected, as they always were.
+		if limit > limits.Max {
+			limit = limits.Max
+		}
+		// Beyond this page (page-1)*limit overflows.
+		if maxPage := math.MaxInt/limit + 1; page > maxPage {
+			q.fail("page", "field.max", maxPage)
+		}
+		if !q.valid() {
+			return
+		}
+
+		// Passing cursor, even empty for the first page, switches to keyset
+		// pagination; page is ignored then.
+		if cursor, ok := c.GetQuery("cursor"); ok {
+			result, err := pvzs.GetPVZPage(c.Request.Context(), filter, cursor, limit)
+			if errors.Is(err, services.ErrInvalidCursor) {
+				q.fail("cursor", "field.cursor")
+				q.valid()
+				return
+			}
+			if err != nil {
+				respondError(c, err)
+				return
+			}
+
+			c.JSON(http.StatusOK, newPVZListResponse(result, limit, q.lang))
+			return
+		}
+
+		if strings.Contains(c.GetHeader("Accept"), LegacyPVZListMediaType) {
+			result, err := pvzs.GetPVZList(c.Request.Context(), filter, page, limit)
+			if err != nil {
+				respondError(c, err)
+				return
+			}
+
+			c.JSON(http.StatusOK, result)
+			return
+		}
+
+		result, err := pvzs.GetPVZPageByNumber(c.Request.Context(), filter, page, limit)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		resp := newPVZListResponse(result, limit, q.lang)
+		resp.Page = page
+		resp.TotalPages = (result.Total + limit - 1) / limit
+		c.JSON(http.StatusOK, resp)
+	}
 }
@@ -2,8 +2,10 @@ package handlers_test
 
 import (
 	"avito-internship/internal/models"
+	"avito-internship/internal/services"
 	"avito-internship/internal/transport/handlers"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"github.com/gin-gonic/gin"
@@ -183,3 +185,321 @@ func TestCreatePVZ_CityNotAllowed(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 	assert.Contains(t, w.Body.String(), "city not allowed")
 }
+
+type pvzListService struct {
+	mock.Mock
+}
+
+func (m *pvzListService) CreatePVZ(_ context.Context, pvz models.PVZ, actorID string) (*models.PVZ, error) {
+	args := m.Called(pvz, actorID)
+	if p := args.Get(0); p != nil {
+		return p.(*models.PVZ), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *pvzListService) GetPVZList(_ context.Context, filter services.PVZFilter, page, limit int) ([]services.PVZWithReceptions, error) {
+	args := m.Called(filter, page, limit)
+	list, _ := args.Get(0).([]services.PVZWithReceptions)
+	return list, args.Error(1)
+}
+
+func (m *pvzListService) GetPVZPageByNumber(_ context.Context, filter services.PVZFilter, page, limit int) (*services.PVZPage, error) {
+	args := m.Called(filter, page, limit)
+	if p := args.Get(0); p != nil {
+		return p.(*services.PVZPage), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *pvzListService) GetPVZPage(_ context.Context, filter services.PVZFilter, cursor string, limit int) (*services.PVZPage, error) {
+	args := m.Called(filter, cursor, limit)
+	if p := args.Get(0); p != nil {
+		return p.(*services.PVZPage), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func setupPVZListRouter(svc handlers.PVZService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/pvz", handlers.GetPVZList(svc, services.PageLimits{Default: 10, Max: 30}))
+	return r
+}
+
+func TestGetPVZList_Envelope(t *testing.T) {
+	svc := new(pvzListService)
+	svc.On("GetPVZPageByNumber", services.PVZFilter{}, 2, 5).
+		Return(&services.PVZPage{
+			Items: []services.PVZWithReceptions{{PVZ: models.PVZ{ID: "pvz-1"}}, {PVZ: models.PVZ{ID: "pvz-2"}}},
+			Stats: map[string]models.PVZStats{
+				"pvz-1": {ReceptionCount: 2, ProductCounts: map[string]int{"обувь": 3}},
+			},
+			Total: 12,
+		}, nil)
+
+	w := httptest.NewRecorder()
+	setupPVZListRouter(svc).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/pvz?page=2&limit=5", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body handlers.PVZListResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 12, body.Total)
+	assert.Equal(t, 2, body.Page)
+	assert.Equal(t, 3, body.TotalPages)
+	assert.Equal(t, 5, body.Limit)
+	assert.Len(t, body.Items, 2)
+	assert.Equal(t, 2, body.Items[0].ReceptionCount)
+	assert.Equal(t, 3, body.Items[0].ProductCounts["обувь"])
+	assert.Equal(t, 0, body.Items[1].ReceptionCount)
+	assert.NotNil(t, body.Items[1].ProductCounts)
+	svc.AssertExpectations(t)
+}
+
+func TestGetPVZList_ItemKeys(t *testing.T) {
+	registered := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+	employee := testEmployeeID
+
+	svc := new(pvzListService)
+	svc.On("GetPVZPageByNumber", services.PVZFilter{}, 1, 10).
+		Return(&services.PVZPage{
+			Items: []services.PVZWithReceptions{{
+				PVZ: models.PVZ{ID: "pvz-1", RegistrationDate: registered, City: "Казань"},
+				Receptions: []services.ReceptionWithProducts{{
+					Reception: models.Reception{ID: "rec-1", DateTime: registered, PVZID: "pvz-1", Status: "in_progress", CreatedBy: &employee},
+					Products:  []models.Product{{ID: "prod-1", DateTime: registered, Type: "обувь", ReceptionID: "rec-1", CreatedBy: &employee}},
+				}},
+			}},
+			Stats: map[string]models.PVZStats{"pvz-1": {ReceptionCount: 1, ProductCounts: map[string]int{"обувь": 1}}},
+			Total: 1,
+		}, nil)
+
+	w := httptest.NewRecorder()
+	setupPVZListRouter(svc).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/pvz", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Items []json.RawMessage `json:"items"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.JSONEq(t, `{
+		"pvz": {"id": "pvz-1", "registrationDate": "2025-04-01T12:00:00Z", "city": "Казань"},
+		"cityName": "Kazan",
+		"receptions": [{
+			"reception": {
+				"id": "rec-1", "dateTime": "2025-04-01T12:00:00Z", "pvzId": "pvz-1", "status": "in_progress",
+				"statusName": "In progress", "createdBy": "`+employee+`", "closedBy": null, "closedAt": null
+			},
+			"products": [{
+				"id": "prod-1", "dateTime": "2025-04-01T12:00:00Z", "type": "обувь", "typeName": "Shoes",
+				"receptionId": "rec-1", "createdBy": "`+employee+`", "deletedBy": null, "deletedAt": null
+			}]
+		}],
+		"receptionCount": 1,
+		"productCounts": {"обувь": 1}
+	}`, string(body.Items[0]))
+	svc.AssertExpectations(t)
+}
+
+func TestGetPVZList_LegacyArray(t *testing.T) {
+	svc := new(pvzListService)
+	svc.On("GetPVZList", services.PVZFilter{}, 2, 5).
+		Return([]services.PVZWithReceptions{{PVZ: models.PVZ{ID: "pvz-1"}}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/pvz?page=2&limit=5", nil)
+	req.Header.Set("Accept", handlers.LegacyPVZListMediaType)
+	w := httptest.NewRecorder()
+	setupPVZListRouter(svc).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body []json.RawMessage
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Len(t, body, 1)
+	svc.AssertExpectations(t)
+}
+
+func TestGetPVZList_Cursor(t *testing.T) {
+	svc := new(pvzListService)
+	svc.On("GetPVZPage", services.PVZFilter{}, "", 10).
+		Return(&services.PVZPage{
+			Items:      []services.PVZWithReceptions{{PVZ: models.PVZ{ID: "pvz-1"}}},
+			NextCursor: "next",
+		}, nil)
+
+	w := httptest.NewRecorder()
+	setupPVZListRouter(svc).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/pvz?cursor=", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body handlers.PVZListResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Len(t, body.Items, 1)
+	assert.Equal(t, "next", body.NextCursor)
+	assert.Zero(t, body.Page)
+	svc.AssertExpectations(t)
+}
+
+func TestGetPVZList_InvalidCursor(t *testing.T) {
+	svc := new(pvzListService)
+	svc.On("GetPVZPage", services.PVZFilter{}, "garbage", 10).
+		Return(nil, services.ErrInvalidCursor)
+
+	w := httptest.NewRecorder()
+	setupPVZListRouter(svc).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/pvz?cursor=garbage", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, []handlers.FieldError{{Field: "cursor", Message: "is not a cursor returned by this endpoint"}},
+		decodeFieldErrors(t, w))
+}
+
+func TestGetPVZList_Filters(t *testing.T) {
+	svc := new(pvzListService)
+	svc.On("GetPVZPageByNumber", services.PVZFilter{
+		Cities:          []string{"Казань", "Москва"},
+		ReceptionStatus: "in_progress",
+		ProductType:     "электроника",
+		IncludeDeleted:  true,
+	}, 1, 10).Return(&services.PVZPage{}, nil)
+
+	url := "/pvz?city=Казань&city=Москва&receptionStatus=in_progress&productType=электроника&includeDeleted=true"
+	w := httptest.NewRecorder()
+	setupPVZListRouter(svc).ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	svc.AssertExpectations(t)
+}
+
+func TestGetPVZList_InvalidFilter(t *testing.T) {
+	for _, query := range []string{"city=Ростов", "receptionStatus=open", "productType=еда"} {
+		svc := new(pvzListService)
+
+		w := httptest.NewRecorder()
+		setupPVZListRouter(svc).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/pvz?"+query, nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		svc.AssertNotCalled(t, "GetPVZPageByNumber")
+	}
+}
+
+func decodeFieldErrors(t *testing.T, w *httptest.ResponseRecorder) []handlers.FieldError {
+	t.Helper()
+
+	var body struct {
+		Message string                `json:"message"`
+		Errors  []handlers.FieldError `json:"errors"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.NotEmpty(t, body.Message)
+	return body.Errors
+}
+
+func TestGetPVZList_RejectsMalformedQuery(t *testing.T) {
+	cases := map[string][]string{
+		"startDate=yesterday": {"startDate"},
+		"endDate=2025-13-01":  {"endDate"},
+		"page=0":              {"page"},
+		"page=two":            {"page"},
+		"includeDeleted=yes":  {"includeDeleted"},
+		"limit=0":             {"limit"},
+		"limit=-5&page=x":     {"page", "limit"},
+		// The offset of this page overflows.
+		"page=9223372036854775807&limit=30":          {"page"},
+		"startDate=2025-04-02&endDate=2025-04-01":    {"endDate"},
+		"startDate=bad&city=Ростов&productType=плащ": {"startDate", "city", "productType"},
+	}
+
+	for query, fields := range cases {
+		t.Run(query, func(t *testing.T) {
+			svc := new(pvzListService)
+
+			w := httptest.NewRecorder()
+			setupPVZListRouter(svc).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/pvz?"+query, nil))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var got []string
+			for _, e := range decodeFieldErrors(t, w) {
+				got = append(got, e.Field)
+			}
+			assert.ElementsMatch(t, fields, got)
+			svc.AssertNotCalled(t, "GetPVZPageByNumber")
+		})
+	}
+}
+
+func TestGetPVZList_DateFormats(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	cases := []struct {
+		query      string
+		start, end time.Time
+	}{
+		{
+			query: "startDate=2025-04-01&endDate=2025-04-02",
+			start: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+			end:   time.Date(2025, 4, 2, 23, 59, 59, 999999000, time.UTC),
+		},
+		{
+			query: "startDate=2025-04-01T10:00:00&endDate=2025-04-01T12:30:00",
+			start: time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC),
+			end:   time.Date(2025, 4, 1, 12, 30, 0, 0, time.UTC),
+		},
+		{
+			query: "startDate=2025-04-01T10:00:00%2B03:00&endDate=2025-04-01T12:00:00Z",
+			start: time.Date(2025, 4, 1, 10, 0, 0, 0, moscow),
+			end:   time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.query, func(t *testing.T) {
+			svc := new(pvzListService)
+			svc.On("GetPVZPageByNumber", mock.MatchedBy(func(f services.PVZFilter) bool {
+				return f.StartDate.Equal(tc.start) && f.EndDate.Equal(tc.end)
+			}), 1, 10).Return(&services.PVZPage{}, nil)
+
+			w := httptest.NewRecorder()
+			setupPVZListRouter(svc).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/pvz?"+tc.query, nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			svc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetPVZList_CapsLimit(t *testing.T) {
+	svc := new(pvzListService)
+	svc.On("GetPVZPageByNumber", services.PVZFilter{}, 1, 30).Return(&services.PVZPage{}, nil)
+
+	w := httptest.NewRecorder()
+	setupPVZListRouter(svc).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/pvz?limit=500", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	svc.AssertExpectations(t)
+}
+
+func TestGetPVZList_LocalizesErrorsAndNames(t *testing.T) {
+	svc := new(pvzListService)
+	req := httptest.NewRequest(http.MethodGet, "/pvz?page=0", nil)
+	req.Header.Set("Accept-Language", "ru-RU,ru;q=0.9,en;q=0.8")
+
+	w := httptest.NewRecorder()
+	setupPVZListRouter(svc).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "ru", w.Header().Get("Content-Language"))
+	assert.Equal(t, []handlers.FieldError{{Field: "page", Message: "должно быть не меньше 1"}}, decodeFieldErrors(t, w))
+	assert.Contains(t, w.Body.String(), `"message":"Некорректный запрос"`)
+
+	svc.On("GetPVZPageByNumber", services.PVZFilter{}, 1, 10).
+		Return(&services.PVZPage{Items: []services.PVZWithReceptions{{PVZ: models.PVZ{ID: "pvz-1", City: "Санкт-Петербург"}}}, Total: 1}, nil)
+	req = httptest.NewRequest(http.MethodGet, "/pvz", nil)
+	req.Header.Set("Accept-Language", "en-US")
+
+	w = httptest.NewRecorder()
+	setupPVZListRouter(svc).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body handlers.PVZListResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "Saint Petersburg", body.Items[0].CityName)
+	assert.Equal(t, "Санкт-Петербург", body.Items[0].PVZ.City)
+	svc.AssertExpectations(t)
+}
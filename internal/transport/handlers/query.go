@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"avito-internship/internal/i18n"
+	"avito-internship/internal/transport/errcode"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultQueryLocation is the time zone of date and date-time query values
+// given without an offset.
+var DefaultQueryLocation = time.UTC
+
+const (
+	dateLayout          = "2006-01-02"
+	localDateTimeLayout = "2006-01-02T15:04:05"
+)
+
+// FieldError describes one rejected request field in the "errors" list of a
+// 400 response.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// queryParser reads query parameters and collects an error for each malformed
+// one, so that a single response can report all of them.
+type queryParser struct {
+	c      *gin.Context
+	lang   string
+	errors []FieldError
+}
+
+func newQueryParser(c *gin.Context) *queryParser {
+	return &queryParser{c: c, lang: language(c)}
+}
+
+// fail records an error for field with the message under key, formatted with
+// args.
+func (p *queryParser) fail(field, key string, args ...interface{}) {
+	p.errors = append(p.errors, FieldError{Field: field, Message: i18n.Text(p.lang, key, args...)})
+}
+
+// valid writes a 400 response listing the collected errors, if any, and
+// reports whether parsing succeeded.
+func (p *queryParser) valid() bool {
+	if len(p.errors) == 0 {
+		return true
+	}
+
+	respondInvalidInput(p.c, errcode.InvalidInput, p.errors...)
+	return false
+}
+
+// integer returns the parameter, or def when it is absent.
+func (p *queryParser) integer(name string, def, min int) int {
+	raw, ok := p.c.GetQuery(name)
+	if !ok {
+		return def
+	}
+
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		p.fail(name, "field.integer")
+		return def
+	}
+	if v < min {
+		p.fail(name, "field.min", min)
+		return def
+	}
+
+	return v
+}
+
+// date accepts RFC 3339 date-times, date-times without an offset and plain
+// dates; the latter two are read in DefaultQueryLocation. A plain date means
+// the start of that day, or its last instant when endOfDay is set, so that a
+// date range includes both boundary days.
+func (p *queryParser) date(name string, endOfDay bool) *time.Time {
+	raw := p.c.Query(name)
+	if raw == "" {
+		return nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return &t
+	}
+	if t, err := time.ParseInLocation(localDateTimeLayout, raw, DefaultQueryLocation); err == nil {
+		return &t
+	}
+	if t, err := time.ParseInLocation(dateLayout, raw, DefaultQueryLocation); err == nil {
+		if endOfDay {
+			// Microseconds match the precision of Postgres timestamps.
+			t = t.AddDate(0, 0, 1).Add(-time.Microsecond)
+		}
+		return &t
+	}
+
+	p.fail(name, "field.date")
+	return nil
+}
+
+// flag reads a boolean parameter; it is false when absent.
+func (p *queryParser) flag(name string) bool {
+	raw, ok := p.c.GetQuery(name)
+	if !ok {
+		return false
+	}
+
+	v, err := strconv.ParseBool(raw)
+	if err != nil {
+		p.fail(name, "field.boolean")
+		return false
+	}
+
+	return v
+}
+
+func (p *queryParser) enum(name string, allowed ...string) string {
+	v := p.c.Query(name)
+	if v != "" && !oneOf(v, allowed) {
+		p.fail(name, "field.one_of", strings.Join(allowed, ", "))
+		return ""
+	}
+
+	return v
+}
+
+// enumList reads a parameter that may be repeated.
+func (p *queryParser) enumList(name string, allowed ...string) []string {
+	values := p.c.QueryArray(name)
+	for _, v := range values {
+		if !oneOf(v, allowed) {
+			p.fail(name, "field.one_of", strings.Join(allowed, ", "))
+			return nil
+		}
+	}
+
+	return values
+}
+
+func oneOf(v string, allowed []string) bool {
+	for _, a := range allowed {
+		if v == a {
+			return true
+		}
+	}
+	return false
+}
@@ -1,51 +1,63 @@
 package handlers
 
 import (
-	"avito-internship/internal/database"
-	"avito-internship/internal/services"
+	"avito-internship/internal/auth"
+	"avito-internship/internal/models"
+	"avito-internship/internal/transport/errcode"
+	"context"
 	"github.com/gin-gonic/gin"
 	"net/http"
 )
 
 type ReceptionRequest struct {
-	PVZID string `json:"pvzId" binding:"required"`
+	PVZID string `json:"pvzId" binding:"required,uuid"`
 }
 
-func CreateReception(c *gin.Context) {
-	role := c.GetString("role")
-	if role != "employee" {
-		c.JSON(http.StatusForbidden, gin.H{"message": "Only employees can create receptions"})
-		return
-	}
-
-	var req ReceptionRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input"})
-		return
-	}
-
-	reception, err := services.CreateReception(database.DB, req.PVZID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
-		return
-	}
-
-	c.JSON(http.StatusCreated, reception)
+type ReceptionService interface {
+	CreateReception(ctx context.Context, pvzID, actorID string) (*models.Reception, error)
+	CloseLastReception(ctx context.Context, pvzID, actorID string) error
 }
 
-func CloseReception(c *gin.Context) {
-	role := c.GetString("role")
-	if role != "employee" {
-		c.JSON(http.StatusForbidden, gin.H{"message": "Only employees can close receptions"})
-		return
+func CreateReception(receptions ReceptionService, policy *auth.Policy, access PVZAccess) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ReceptionRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondInvalidInput(c, errcode.InvalidInput)
+			return
+		}
+
+		if !authorizePVZ(c, policy, access, req.PVZID) {
+			return
+		}
+
+		reception, err := receptions.CreateReception(c.Request.Context(), req.PVZID, c.GetString("userID"))
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, reception)
 	}
+}
 
-	pvzID := c.Param("pvzId")
-	err := services.CloseLastReception(database.DB, pvzID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
-		return
+func CloseReception(receptions ReceptionService, policy *auth.Policy, access PVZAccess) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var uri PVZURI
+		if err := c.ShouldBindUri(&uri); err != nil {
+			respondInvalidInput(c, errcode.InvalidInput)
+			return
+		}
+
+		if !authorizePVZ(c, policy, access, uri.PVZID) {
+			return
+		}
+
+		err := receptions.CloseLastReception(c.Request.Context(), uri.PVZID, c.GetString("userID"))
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		respondMessage(c, http.StatusOK, "ok.reception_closed")
 	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "reception has been closed"})
 }
@@ -1,9 +1,11 @@
 package handlers_test
 
 import (
-	"avito-internship/internal/database"
+	"avito-internship/internal/models"
+	"avito-internship/internal/services"
 	"avito-internship/internal/transport/handlers"
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -11,60 +13,65 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
-func TestCreateReceptionHandler_InvalidJSON(t *testing.T) {
-	gin.SetMode(gin.TestMode)
-	router := gin.New()
-	router.POST("/receptions", func(c *gin.Context) {
-		c.Set("role", "employee")
-		handlers.CreateReception(c)
-	})
+type mockReceptionService struct {
+	mock.Mock
+}
 
-	req := httptest.NewRequest(http.MethodPost, "/receptions", bytes.NewBufferString("{ invalid json"))
-	req.Header.Set("Content-Type", "application/json")
-	rr := httptest.NewRecorder()
-	router.ServeHTTP(rr, req)
+func (m *mockReceptionService) CreateReception(_ context.Context, pvzID, actorID string) (*models.Reception, error) {
+	args := m.Called(pvzID, actorID)
+	if r := args.Get(0); r != nil {
+		return r.(*models.Reception), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
 
-	require.Equal(t, http.StatusBadRequest, rr.Code)
+func (m *mockReceptionService) CloseLastReception(_ context.Context, pvzID, actorID string) error {
+	args := m.Called(pvzID, actorID)
+	return args.Error(0)
 }
 
-func TestCreateReceptionHandler_Forbidden(t *testing.T) {
+func TestCreateReceptionHandler_InvalidJSON(t *testing.T) {
+	receptions := new(mockReceptionService)
+
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
 	router.POST("/receptions", func(c *gin.Context) {
-		c.Set("role", "client")
-		handlers.CreateReception(c)
+		c.Set("role", "employee")
+		c.Set("userID", testEmployeeID)
+		handlers.CreateReception(receptions, testPolicy, newAssignmentService(nil))(c)
 	})
 
-	body := `{"pvzId":"any-id"}`
-	req := httptest.NewRequest(http.MethodPost, "/receptions", bytes.NewBufferString(body))
+	req := httptest.NewRequest(http.MethodPost, "/receptions", bytes.NewBufferString("{ invalid json"))
 	req.Header.Set("Content-Type", "application/json")
 	rr := httptest.NewRecorder()
 	router.ServeHTTP(rr, req)
 
-	require.Equal(t, http.StatusForbidden, rr.Code)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
 }
 
 func TestCreateReceptionHandler_RepoError(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
 	defer db.Close()
-	database.DB = db
 
 	pvzID := "31ae2e29-0460-4748-a9f3-2b5747f78960"
 
-	rows := sqlmock.NewRows([]string{"id"}).AddRow("existing_id")
-	mock.ExpectQuery(`SELECT id FROM receptions`).
-		WithArgs(pvzID).
-		WillReturnRows(rows)
+	expectAssigned(mock, pvzID, true)
+
+	receptions := new(mockReceptionService)
+	receptions.On("CreateReception", pvzID, testEmployeeID).
+		Return(nil, services.ErrReceptionAlreadyOpen)
 
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
 	router.POST("/receptions", func(c *gin.Context) {
 		c.Set("role", "employee")
-		handlers.CreateReception(c)
+		c.Set("userID", testEmployeeID)
+		handlers.CreateReception(receptions, testPolicy, newAssignmentService(db))(c)
 	})
 
 	body := `{"pvzId":"` + pvzID + `"}`
@@ -73,32 +80,33 @@ func TestCreateReceptionHandler_RepoError(t *testing.T) {
 	rr := httptest.NewRecorder()
 	router.ServeHTTP(rr, req)
 
-	require.Equal(t, http.StatusBadRequest, rr.Code)
+	require.Equal(t, http.StatusConflict, rr.Code)
 
 	var resp map[string]string
 	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
-	require.Equal(t, "already an open reception", resp["message"])
+	require.Equal(t, "reception_already_open", resp["code"])
 
 	require.NoError(t, mock.ExpectationsWereMet())
+	receptions.AssertExpectations(t)
 }
 
 func TestCloseReceptionHandler_Success(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
 	defer db.Close()
-	database.DB = db
 
 	pvzID := "82cc7cda-bd24-468f-b7b7-844d66b6693c"
 
-	mock.ExpectExec(`UPDATE receptions`).
-		WithArgs(pvzID).
-		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectAssigned(mock, pvzID, true)
+	receptions := new(mockReceptionService)
+	receptions.On("CloseLastReception", pvzID, testEmployeeID).Return(nil)
 
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
 	router.POST("/pvz/:pvzId/close_last_reception", func(c *gin.Context) {
 		c.Set("role", "employee")
-		handlers.CloseReception(c)
+		c.Set("userID", testEmployeeID)
+		handlers.CloseReception(receptions, testPolicy, newAssignmentService(db))(c)
 	})
 
 	req := httptest.NewRequest(http.MethodPost, "/pvz/"+pvzID+"/close_last_reception", nil)
@@ -109,27 +117,44 @@ func TestCloseReceptionHandler_Success(t *testing.T) {
 
 	var resp map[string]string
 	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
-	require.Equal(t, "reception has been closed", resp["message"])
+	require.Equal(t, "Reception has been closed", resp["message"])
 
 	require.NoError(t, mock.ExpectationsWereMet())
+	receptions.AssertExpectations(t)
+}
+
+func TestCloseReceptionHandler_InvalidPVZID(t *testing.T) {
+	receptions := new(mockReceptionService)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/pvz/:pvzId/close_last_reception", handlers.CloseReception(receptions, testPolicy, newAssignmentService(nil)))
+
+	req := httptest.NewRequest(http.MethodPost, "/pvz/123/close_last_reception", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	require.Contains(t, rr.Body.String(), `"code":"invalid_input"`)
+	receptions.AssertNotCalled(t, "CloseLastReception")
 }
 
 func TestCloseReceptionHandler_NoActiveReception(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
 	defer db.Close()
-	database.DB = db
 
-	pvzID := "123"
-	mock.ExpectExec(`UPDATE receptions`).
-		WithArgs(pvzID).
-		WillReturnResult(sqlmock.NewResult(0, 0))
+	pvzID := "31ae2e29-0460-4748-a9f3-2b5747f78960"
+	expectAssigned(mock, pvzID, true)
+	receptions := new(mockReceptionService)
+	receptions.On("CloseLastReception", pvzID, testEmployeeID).Return(services.ErrNoActiveReception)
 
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
 	router.POST("/pvz/:pvzId/close_last_reception", func(c *gin.Context) {
 		c.Set("role", "employee")
-		handlers.CloseReception(c)
+		c.Set("userID", testEmployeeID)
+		handlers.CloseReception(receptions, testPolicy, newAssignmentService(db))(c)
 	})
 
 	req := httptest.NewRequest(http.MethodPost, "/pvz/"+pvzID+"/close_last_reception", nil)
@@ -139,22 +164,37 @@ func TestCloseReceptionHandler_NoActiveReception(t *testing.T) {
 	require.Equal(t, http.StatusBadRequest, rr.Code)
 	var resp map[string]string
 	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
-	require.Equal(t, "no active reception", resp["message"])
+	require.Equal(t, "no_active_reception", resp["code"])
 
 	require.NoError(t, mock.ExpectationsWereMet())
+	receptions.AssertExpectations(t)
 }
 
-func TestCloseReceptionHandler_Forbidden(t *testing.T) {
+func TestCreateReceptionHandler_NotAssigned(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	pvzID := "31ae2e29-0460-4748-a9f3-2b5747f78960"
+	expectAssigned(mock, pvzID, false)
+	receptions := new(mockReceptionService)
+
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
-	router.POST("/pvz/:pvzId/close_last_reception", func(c *gin.Context) {
-		c.Set("role", "client")
-		handlers.CloseReception(c)
+	router.POST("/receptions", func(c *gin.Context) {
+		c.Set("role", "employee")
+		c.Set("userID", testEmployeeID)
+		handlers.CreateReception(receptions, testPolicy, newAssignmentService(db))(c)
 	})
 
-	req := httptest.NewRequest(http.MethodPost, "/pvz/111/close_last_reception", nil)
+	body := `{"pvzId":"` + pvzID + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/receptions", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
 	rr := httptest.NewRecorder()
 	router.ServeHTTP(rr, req)
 
 	require.Equal(t, http.StatusForbidden, rr.Code)
+	require.Contains(t, rr.Body.String(), "Employee is not assigned to this PVZ")
+	require.NoError(t, mock.ExpectationsWereMet())
+	receptions.AssertExpectations(t)
 }
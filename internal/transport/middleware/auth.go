@@ -1,26 +1,63 @@
 package middleware
 
 import (
+	"avito-internship/internal/auth"
+	"avito-internship/internal/i18n"
+	"avito-internship/internal/transport/errcode"
+	"avito-internship/internal/transport/handlers"
+	"context"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strings"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// abort ends the request with a body shaped like handlers.ErrorResponse,
+// worded in the language negotiated from Accept-Language.
+func abort(c *gin.Context, status int, code string) {
+	lang := i18n.Negotiate(c.GetHeader("Accept-Language"))
+	c.Header("Content-Language", lang)
+	c.AbortWithStatusJSON(status, gin.H{"code": code, "message": i18n.Text(lang, code)})
+}
+
+// SessionChecker reports whether a server-side session is still usable.
+type SessionChecker interface {
+	IsSessionActive(ctx context.Context, sessionID string) (bool, error)
+}
+
+func AuthMiddleware(tokens *auth.TokenManager, policy *auth.Policy, sessions SessionChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if !strings.HasPrefix(authHeader, "Bearer ") {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "Missing token"})
+			abort(c, http.StatusUnauthorized, errcode.MissingToken)
 			return
 		}
 
-		token := strings.TrimPrefix(authHeader, "Bearer ")
-		if token != "moderator" && token != "employee" {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "Invalid role"})
+		claims, err := tokens.Parse(strings.TrimPrefix(authHeader, "Bearer "))
+		if err != nil {
+			abort(c, http.StatusUnauthorized, errcode.InvalidToken)
 			return
 		}
 
-		c.Set("role", token)
+		if !policy.HasRole(claims.Role) {
+			abort(c, http.StatusForbidden, errcode.InvalidRole)
+			return
+		}
+
+		if claims.SessionID != "" {
+			active, err := sessions.IsSessionActive(c.Request.Context(), claims.SessionID)
+			if err != nil {
+				handlers.AbortWithError(c, err)
+				return
+			}
+			if !active {
+				abort(c, http.StatusUnauthorized, errcode.SessionRevoked)
+				return
+			}
+		}
+
+		c.Set("role", claims.Role)
+		c.Set("userID", claims.UserID())
+		c.Set("sessionID", claims.SessionID)
 		c.Next()
 	}
 }
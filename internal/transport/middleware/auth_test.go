@@ -1,44 +1,71 @@
 package middleware_test
 
 import (
+	"avito-internship/internal/auth"
 	"avito-internship/internal/transport/middleware"
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-func setupRouter() *gin.Engine {
+func newTokenManager(t *testing.T) *auth.TokenManager {
+	tokens, err := auth.NewTokenManager(map[string][]byte{"test": []byte("secret")}, "test", time.Hour, time.Hour)
+	require.NoError(t, err)
+	return tokens
+}
+
+// sessionStates maps session IDs to whether they are still active.
+type sessionStates map[string]bool
+
+func (s sessionStates) IsSessionActive(_ context.Context, sessionID string) (bool, error) {
+	return s[sessionID], nil
+}
+
+func setupRouter(tokens *auth.TokenManager, sessions middleware.SessionChecker) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
+	router.Use(middleware.AuthMiddleware(tokens, auth.NewPolicy(auth.DefaultPolicyConfig), sessions))
 	router.GET("/test", func(c *gin.Context) {
 		role := c.GetString("role")
-		c.JSON(http.StatusOK, gin.H{"role": role})
+		c.JSON(http.StatusOK, gin.H{"role": role, "userID": c.GetString("userID")})
 	})
 	return router
 }
 
 func TestAuthMiddleware_ValidModerator(t *testing.T) {
-	router := setupRouter()
+	tokens := newTokenManager(t)
+	router := setupRouter(tokens, sessionStates{})
+
+	token, err := tokens.Issue("user-1", "moderator", "")
+	require.NoError(t, err)
 
 	request, _ := http.NewRequest(http.MethodGet, "/test", nil)
-	request.Header.Set("Authorization", "Bearer moderator")
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response := httptest.NewRecorder()
 	router.ServeHTTP(response, request)
 
 	assert.Equal(t, http.StatusOK, response.Code)
 	assert.Contains(t, response.Body.String(), "moderator")
+	assert.Contains(t, response.Body.String(), "user-1")
 }
 
 func TestAuthMiddleware_ValidEmployee(t *testing.T) {
-	router := setupRouter()
+	tokens := newTokenManager(t)
+	router := setupRouter(tokens, sessionStates{})
+
+	token, err := tokens.Issue("user-2", "employee", "")
+	require.NoError(t, err)
 
 	request, _ := http.NewRequest(http.MethodGet, "/test", nil)
-	request.Header.Set("Authorization", "Bearer employee")
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response := httptest.NewRecorder()
 	router.ServeHTTP(response, request)
@@ -48,21 +75,38 @@ func TestAuthMiddleware_ValidEmployee(t *testing.T) {
 }
 
 func TestAuthMiddleware_MissingToken(t *testing.T) {
-	router := setupRouter()
+	router := setupRouter(newTokenManager(t), sessionStates{})
 
 	request, _ := http.NewRequest(http.MethodGet, "/test", nil)
 	resp := httptest.NewRecorder()
 	router.ServeHTTP(resp, request)
 
 	assert.Equal(t, http.StatusUnauthorized, resp.Code)
-	assert.Contains(t, resp.Body.String(), "Missing token")
+	assert.Contains(t, resp.Body.String(), `"code":"missing_token"`)
 }
 
 func TestAuthMiddleware_InvalidToken(t *testing.T) {
-	router := setupRouter()
+	router := setupRouter(newTokenManager(t), sessionStates{})
 
 	request, _ := http.NewRequest(http.MethodGet, "/test", nil)
-	request.Header.Set("Authorization", "Bearer noname")
+	request.Header.Set("Authorization", "Bearer moderator")
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	assert.Contains(t, response.Body.String(), "Invalid token")
+}
+
+func TestAuthMiddleware_InvalidRole(t *testing.T) {
+	tokens := newTokenManager(t)
+	router := setupRouter(tokens, sessionStates{})
+
+	token, err := tokens.Issue("user-3", "noname", "")
+	require.NoError(t, err)
+
+	request, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	request.Header.Set("Authorization", "Bearer "+token)
 
 	response := httptest.NewRecorder()
 	router.ServeHTTP(response, request)
@@ -70,3 +114,82 @@ func TestAuthMiddleware_InvalidToken(t *testing.T) {
 	assert.Equal(t, http.StatusForbidden, response.Code)
 	assert.Contains(t, response.Body.String(), "Invalid role")
 }
+
+func TestAuthMiddleware_ActiveSession(t *testing.T) {
+	tokens := newTokenManager(t)
+	router := setupRouter(tokens, sessionStates{"session-1": true})
+
+	token, err := tokens.Issue("user-1", "employee", "session-1")
+	require.NoError(t, err)
+
+	request, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	request.Header.Set("Authorization", "Bearer "+token)
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+}
+
+type failingSessions struct{}
+
+func (failingSessions) IsSessionActive(context.Context, string) (bool, error) {
+	return false, errors.New("pq: connection refused")
+}
+
+func TestAuthMiddleware_SessionCheckError(t *testing.T) {
+	tokens := newTokenManager(t)
+	router := setupRouter(tokens, failingSessions{})
+
+	token, err := tokens.Issue("user-1", "employee", "session-1")
+	require.NoError(t, err)
+
+	request, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	request.Header.Set("Authorization", "Bearer "+token)
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+	assert.JSONEq(t, `{"code":"internal_error","message":"Internal server error"}`, response.Body.String())
+}
+
+type slowSessions struct{}
+
+func (slowSessions) IsSessionActive(context.Context, string) (bool, error) {
+	return false, context.DeadlineExceeded
+}
+
+func TestAuthMiddleware_SessionCheckTimeout(t *testing.T) {
+	tokens := newTokenManager(t)
+	router := setupRouter(tokens, slowSessions{})
+
+	token, err := tokens.Issue("user-1", "employee", "session-1")
+	require.NoError(t, err)
+
+	request, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	request.Header.Set("Authorization", "Bearer "+token)
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusGatewayTimeout, response.Code)
+	assert.Contains(t, response.Body.String(), `"code":"timeout"`)
+}
+
+func TestAuthMiddleware_RevokedSession(t *testing.T) {
+	tokens := newTokenManager(t)
+	router := setupRouter(tokens, sessionStates{"session-1": false})
+
+	token, err := tokens.Issue("user-1", "employee", "session-1")
+	require.NoError(t, err)
+
+	request, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	request.Header.Set("Authorization", "Bearer "+token)
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	assert.Contains(t, response.Body.String(), `"code":"session_revoked"`)
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"avito-internship/internal/metrics"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"time"
+)
+
+// unmatchedRoute labels requests no route matched, so that arbitrary paths do
+// not create new series.
+const unmatchedRoute = "unmatched"
+
+// Metrics records the count and duration of requests by route template, e.g.
+// /pvz/:pvzId/close_last_reception, and status. It must run outside
+// gin.Recovery to see the requests that panic.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		metrics.HTTPRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,53 @@
+package middleware_test
+
+import (
+	"avito-internship/internal/metrics"
+	"avito-internship/internal/transport/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics_LabelsByRouteTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Metrics())
+	router.POST("/pvz/:pvzId/close_last_reception", func(c *gin.Context) {
+		c.Status(http.StatusConflict)
+	})
+
+	route := metrics.HTTPRequests.WithLabelValues(http.MethodPost, "/pvz/:pvzId/close_last_reception", "409")
+	unmatched := metrics.HTTPRequests.WithLabelValues(http.MethodGet, "unmatched", "404")
+	before, beforeUnmatched := testutil.ToFloat64(route), testutil.ToFloat64(unmatched)
+
+	for _, id := range []string{"a", "b"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/pvz/"+id+"/close_last_reception", nil))
+	}
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/no/such/path", nil))
+
+	assert.Equal(t, before+2, testutil.ToFloat64(route))
+	assert.Equal(t, beforeUnmatched+1, testutil.ToFloat64(unmatched))
+	assert.Positive(t, testutil.CollectAndCount(metrics.HTTPRequestDuration, "pvz_http_request_duration_seconds"))
+}
+
+func TestMetrics_CountsPanics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Metrics(), gin.Recovery())
+	router.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	failed := metrics.HTTPRequests.WithLabelValues(http.MethodGet, "/panic", "500")
+	before := testutil.ToFloat64(failed)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, before+1, testutil.ToFloat64(failed))
+}
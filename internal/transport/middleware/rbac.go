@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"avito-internship/internal/auth"
+	"avito-internship/internal/transport/errcode"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// RequirePermission rejects requests whose role may not perform the action.
+// Whether the role is limited to assigned PVZs is checked by the handlers,
+// once they know the target PVZ.
+func RequirePermission(policy *auth.Policy, action auth.Action) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("role")
+		if !policy.Allows(role, action) {
+			abort(c, http.StatusForbidden, errcode.AccessDenied)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,80 @@
+package middleware_test
+
+import (
+	"avito-internship/internal/auth"
+	"avito-internship/internal/transport/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupRBACRouter(policy *auth.Policy, action auth.Action) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("role", c.GetHeader("Role"))
+		c.Next()
+	})
+	router.GET("/test", middleware.RequirePermission(policy, action), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestRequirePermission_DefaultPolicy(t *testing.T) {
+	policy := auth.NewPolicy(auth.DefaultPolicyConfig)
+
+	cases := []struct {
+		action auth.Action
+		role   string
+		status int
+	}{
+		{auth.ActionCreatePVZ, auth.RoleModerator, http.StatusOK},
+		{auth.ActionCreatePVZ, auth.RoleEmployee, http.StatusForbidden},
+		{auth.ActionListPVZ, auth.RoleEmployee, http.StatusOK},
+		{auth.ActionCreateReception, auth.RoleEmployee, http.StatusOK},
+		{auth.ActionCreateReception, auth.RoleModerator, http.StatusForbidden},
+		{auth.ActionCloseReception, auth.RoleModerator, http.StatusForbidden},
+		{auth.ActionAddProduct, auth.RoleModerator, http.StatusForbidden},
+		{auth.ActionDeleteProduct, auth.RoleModerator, http.StatusForbidden},
+		{auth.ActionDeleteProduct, auth.RoleAdmin, http.StatusOK},
+		{auth.ActionManageEmployees, auth.RoleEmployee, http.StatusForbidden},
+		{auth.ActionRevokeSessions, auth.RoleEmployee, http.StatusForbidden},
+		{auth.ActionRevokeSessions, auth.RoleAdmin, http.StatusOK},
+		{auth.ActionViewAudit, auth.RoleEmployee, http.StatusForbidden},
+		{auth.ActionViewAudit, auth.RoleModerator, http.StatusOK},
+		{auth.ActionListPVZ, "client", http.StatusForbidden},
+	}
+
+	for _, tc := range cases {
+		router := setupRBACRouter(policy, tc.action)
+
+		request, _ := http.NewRequest(http.MethodGet, "/test", nil)
+		request.Header.Set("Role", tc.role)
+
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+
+		assert.Equal(t, tc.status, response.Code, "%s as %s", tc.action, tc.role)
+	}
+}
+
+func TestRequirePermission_CustomPolicy(t *testing.T) {
+	policy := auth.NewPolicy(auth.PolicyConfig{
+		Permissions: map[auth.Action][]string{
+			auth.ActionCloseReception: {auth.RoleEmployee, auth.RoleModerator},
+		},
+	})
+	router := setupRBACRouter(policy, auth.ActionCloseReception)
+
+	request, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	request.Header.Set("Role", auth.RoleModerator)
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+}
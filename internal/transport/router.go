@@ -1,26 +1,70 @@
 package transport
 
 import (
+	"avito-internship/internal/auth"
+	"avito-internship/internal/health"
+	"avito-internship/internal/services"
 	"avito-internship/internal/transport/handlers"
 	"avito-internship/internal/transport/middleware"
 	"github.com/gin-gonic/gin"
+	"time"
 )
 
-func SetupRouter() *gin.Engine {
-	r := gin.Default()
+// Options are the router settings that vary between deployments.
+type Options struct {
+	EnableDummyLogin bool
+	PageLimits       services.PageLimits
+	// Readiness backs /readyz; without it the instance is always ready.
+	Readiness handlers.ReadinessProbe
+	// ReadinessTimeout bounds the checks of one /readyz request.
+	ReadinessTimeout time.Duration
+}
+
+func SetupRouter(tokens *auth.TokenManager, policy *auth.Policy, svc services.Services, opts Options) *gin.Engine {
+	// Metrics goes before Recovery, so that panicking requests are counted
+	// with the 500 Recovery responds with.
+	r := gin.New()
+	r.Use(gin.Logger(), middleware.Metrics(), gin.Recovery())
+
+	readiness := opts.Readiness
+	if readiness == nil {
+		readiness = health.NewReadiness()
+	}
+	r.GET("/healthz", handlers.Liveness())
+	r.GET("/readyz", handlers.Readiness(readiness, opts.ReadinessTimeout))
+
+	if opts.EnableDummyLogin {
+		r.POST("/dummyLogin", handlers.DummyLogin(tokens))
+	}
+	r.POST("/register", handlers.Register(svc.Users))
+	r.POST("/login", handlers.Login(tokens, svc.Users, svc.Sessions))
+	r.POST("/token/refresh", handlers.RefreshToken(tokens, svc.Sessions))
+	r.GET("/dictionary", handlers.GetDictionary())
 
-	r.POST("/dummyLogin", handlers.DummyLogin)
+	can := func(action auth.Action) gin.HandlerFunc {
+		return middleware.RequirePermission(policy, action)
+	}
 
-	auth := r.Group("/", middleware.AuthMiddleware())
+	authorized := r.Group("/", middleware.AuthMiddleware(tokens, policy, svc.Sessions))
 	{
-		auth.POST("/pvz", handlers.CreatePVZ)
-		auth.GET("/pvz", handlers.GetPVZList)
+		authorized.POST("/logout", handlers.Logout(svc.Sessions))
+		authorized.POST("/users", can(auth.ActionCreateUser), handlers.CreateUser(svc.Users))
+		authorized.POST("/users/:userId/logout", can(auth.ActionRevokeSessions), handlers.RevokeUserSessions(svc.Sessions))
+
+		authorized.POST("/pvz", can(auth.ActionCreatePVZ), handlers.CreatePVZ(svc.PVZ))
+		authorized.GET("/pvz", can(auth.ActionListPVZ), handlers.GetPVZList(svc.PVZ, opts.PageLimits))
+
+		authorized.GET("/pvz/:pvzId/employees", can(auth.ActionManageEmployees), handlers.GetPVZEmployees(svc.Assignments))
+		authorized.POST("/pvz/:pvzId/employees", can(auth.ActionManageEmployees), handlers.AssignEmployee(svc.Assignments))
+		authorized.DELETE("/pvz/:pvzId/employees/:userId", can(auth.ActionManageEmployees), handlers.UnassignEmployee(svc.Assignments))
+
+		authorized.POST("/receptions", can(auth.ActionCreateReception), handlers.CreateReception(svc.Receptions, policy, svc.Assignments))
+		authorized.POST("/pvz/:pvzId/close_last_reception", can(auth.ActionCloseReception), handlers.CloseReception(svc.Receptions, policy, svc.Assignments))
 
-		auth.POST("/receptions", handlers.CreateReception)
-		auth.POST("/pvz/:pvzId/close_last_reception", handlers.CloseReception)
+		authorized.POST("/products", can(auth.ActionAddProduct), handlers.AddProduct(svc.Products, policy, svc.Assignments))
+		authorized.POST("/pvz/:pvzId/delete_last_product", can(auth.ActionDeleteProduct), handlers.DeleteLastProduct(svc.Products, policy, svc.Assignments))
 
-		auth.POST("/products", handlers.AddProduct)
-		auth.POST("/pvz/:pvzId/delete_last_product", handlers.DeleteLastProduct)
+		authorized.GET("/audit", can(auth.ActionViewAudit), handlers.GetAuditLog(svc.Audit))
 	}
 
 	return r